@@ -3,16 +3,19 @@ package main
 import (
 	"context"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
+	"net/http"
 	"time"
 
 	"github.com/Ridwan414/goexpress"
 )
 
+func hello(c *goexpress.Context) {
+	c.String(http.StatusOK, "Hello from GoExpress!\nYou requested: %s %s\n", c.Request.Method, c.Request.URL.Path)
+}
+
 func exampleDefaultEngine() {
 	app := goexpress.New()
+	app.GET("/", hello)
 	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
@@ -25,40 +28,53 @@ func exampleCustomConfig() {
 		WriteTimeout: 12 * time.Second,
 	}
 	app := goexpress.NewWithConfig(config)
+	app.GET("/", hello)
 	if err := app.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func exampleGracefulShutdown() {
-	// Create new engine
+func exampleRouting() {
 	app := goexpress.New()
+	app.Use(goexpress.Logger(), goexpress.Recover())
 
-	// Start server in background goroutine
-	go func() {
-		log.Println("Starting server...")
-		if err := app.Run(); err != nil {
-			log.Printf("Server error: %v\n", err)
-		}
-	}()
+	api := app.Group("/api")
+	v1 := api.Group("/v1")
+	v1.GET("/users", func(c *goexpress.Context) {
+		c.JSON(http.StatusOK, []string{"alice", "bob"})
+	})
+	v1.POST("/users", func(c *goexpress.Context) {
+		c.String(http.StatusCreated, "User created\n")
+	})
+	v1.GET("/users/:id", func(c *goexpress.Context) {
+		c.String(http.StatusOK, "User: %s\n", c.Param("id"))
+	})
 
-	// Create channel to listen for interrupt signals
-	quit := make(chan os.Signal, 1)
-
-	// Register to receive SIGINT (Ctrl+C) and SIGTERM
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-
-	// Block until we receive a signal
-	<-quit
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
 
-	log.Println("Shutdown signal received...")
+func exampleGracefulShutdown() {
+	// Create new engine
+	app := goexpress.New()
+	app.GET("/", hello)
 
-	// Create context with 5 second timeout for shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	// Release resources once in-flight requests have drained; hooks run
+	// in reverse registration order and share the shutdown deadline
+	app.OnShutdown(func(ctx context.Context) error {
+		log.Println("Closing database pool...")
+		return nil
+	})
+	app.OnShutdown(func(ctx context.Context) error {
+		log.Println("Flushing logs...")
+		return nil
+	})
 
-	// Attempt graceful shutdown
-	if err := app.Shutdown(ctx); err != nil {
+	// Serve until SIGINT (Ctrl+C) or SIGTERM, then shut down gracefully
+	// with a 5 second timeout
+	log.Println("Starting server...")
+	if err := app.RunUntilSignal(5 * time.Second); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
@@ -69,5 +85,6 @@ func main() {
 	// Uncomment the desired example to run:
 	// exampleDefaultEngine()
 	// exampleCustomConfig()
+	// exampleRouting()
 	exampleGracefulShutdown()
 }
@@ -0,0 +1,239 @@
+package goexpress
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Validator is implemented by types that check their own fields once
+// BindAll has populated them.
+type Validator interface {
+	Validate() error
+}
+
+// BindPath sets the fields of the struct pointed to by v from the path
+// parameters of the matched route. A field tagged `uri:"id"` receives the
+// ":id" parameter; fields whose parameter was not captured are left
+// unchanged. Fields may be strings, booleans, integers, floats, or
+// implement encoding.TextUnmarshaler. A value that does not parse is
+// reported as an *HTTPError with status 400 that wraps the parse error.
+func (c *Context) BindPath(v interface{}) error {
+	return bindFields(v, "uri", "path parameter", func(name string) ([]string, bool) {
+		value, ok := c.params.lookup(name)
+		return []string{value}, ok
+	})
+}
+
+// BindAll populates the struct pointed to by v from the request body, the
+// URL query and the path parameters, then validates it. A non-empty body
+// is decoded as in BindJSON, using the `json` tags. Next, fields tagged
+// `query:"name"` are set from the query, where slice fields receive every
+// value of a repeated parameter. Finally, fields tagged `uri` are set as
+// in BindPath. Each step overwrites what the previous one set, so path
+// parameters take precedence over the query, which takes precedence over
+// the body. If v implements Validator, its Validate method is called last.
+// Every error caused by the request is an *HTTPError ready for c.Error: a
+// validation error becomes a 400 with its text as message and the
+// original error as cause, unless it already is an HTTPError.
+func (c *Context) BindAll(v interface{}) error {
+	if body := c.Request.Body; body != nil && body != http.NoBody && c.Request.ContentLength != 0 {
+		if err := c.BindJSON(v); err != nil {
+			return err
+		}
+	}
+
+	query := c.queryValues()
+	err := bindFields(v, "query", "query parameter", func(name string) ([]string, bool) {
+		values, ok := query[name]
+		return values, ok
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.BindPath(v); err != nil {
+		return err
+	}
+
+	if val, ok := v.(Validator); ok {
+		if err := val.Validate(); err != nil {
+			var he *HTTPError
+			if errors.As(err, &he) {
+				return err
+			}
+			return &HTTPError{Status: http.StatusBadRequest, Message: err.Error(), Err: err}
+		}
+	}
+	return nil
+}
+
+// BindJSON decodes the JSON request body into v, which must be a pointer.
+// The request must have a Content-Type of application/json or a "+json"
+// type such as application/merge-patch+json, and the body must hold a
+// single JSON value no larger than Config.MaxJSONBodySize. With
+// Config.DisallowUnknownJSONFields, object keys that match no field of v
+// are rejected. The returned error is an *HTTPError ready for c.Error:
+// 415 for a wrong content type, 413 for an oversized body, and 400 for an
+// empty or malformed body, with the byte offset of syntax and type errors
+// in its message.
+func (c *Context) BindJSON(v interface{}) error {
+	if !isJSONMediaType(c.Request.Header.Get("Content-Type")) {
+		return NewHTTPError(http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+	}
+	if c.Request.Body == nil {
+		return NewHTTPError(http.StatusBadRequest, "request body is empty")
+	}
+
+	limit := c.engine.config.MaxJSONBodySize
+	if limit == 0 {
+		limit = defaultMaxJSONBodySize
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, limit))
+	if c.engine.config.DisallowUnknownJSONFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return jsonBodyError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return &HTTPError{Status: http.StatusBadRequest, Message: "request body must hold a single JSON value", Err: err}
+	}
+	return nil
+}
+
+// isJSONMediaType reports whether contentType is application/json or an
+// application type with the "+json" structured syntax suffix.
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/json" ||
+		strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json")
+}
+
+// defaultMaxJSONBodySize is the BindJSON body limit when
+// Config.MaxJSONBodySize is zero.
+const defaultMaxJSONBodySize = 1 << 20
+
+// jsonBodyError converts a decoding error from BindJSON into an HTTPError
+// describing it to the client.
+func jsonBodyError(err error) *HTTPError {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxErr *http.MaxBytesError
+	var msg string
+	switch {
+	case errors.As(err, &maxErr):
+		return &HTTPError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: fmt.Sprintf("request body exceeds %d bytes", maxErr.Limit),
+			Err:     err,
+		}
+	case errors.As(err, &syntaxErr):
+		msg = fmt.Sprintf("malformed JSON at offset %d: %v", syntaxErr.Offset, syntaxErr)
+	case errors.As(err, &typeErr):
+		msg = fmt.Sprintf("invalid value for field %q at offset %d: expected %s, got JSON %s",
+			typeErr.Field, typeErr.Offset, typeErr.Type, typeErr.Value)
+	case err == io.EOF:
+		msg = "request body is empty"
+	case err == io.ErrUnexpectedEOF:
+		msg = "malformed JSON: unexpected end of body"
+	default:
+		// Unknown fields and invalid targets have no dedicated error type
+		msg = strings.TrimPrefix(err.Error(), "json: ")
+	}
+	return &HTTPError{Status: http.StatusBadRequest, Message: msg, Err: err}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// bindFields sets each exported field of the struct pointed to by v that
+// has the given tag to the values returned by lookup for the tag name.
+func bindFields(v interface{}, tag, source string, lookup func(name string) ([]string, bool)) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("goexpress: bind target must be a non-nil pointer to a struct")
+	}
+	rv = rv.Elem()
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get(tag)
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		values, ok := lookup(name)
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if err := setField(rv.Field(i), values); err != nil {
+			return &HTTPError{
+				Status:  http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid %s %q", source, name),
+				Err:     err,
+			}
+		}
+	}
+	return nil
+}
+
+// setField parses values into fv. Slice fields receive all values, other
+// fields the first one.
+func setField(fv reflect.Value, values []string) error {
+	if fv.Kind() == reflect.Slice && !fv.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, s := range values {
+			if err := setValue(slice.Index(i), s); err != nil {
+				return err
+			}
+		}
+		fv.Set(slice)
+		return nil
+	}
+	return setValue(fv, values[0])
+}
+
+// setValue parses s into the scalar value fv.
+func setValue(fv reflect.Value, s string) error {
+	if u, ok := fv.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package goexpress
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type updateUser struct {
+	ID     int       `uri:"id" json:"id"`
+	Org    string    `uri:"org" query:"org" json:"org"`
+	Name   string    `json:"name"`
+	Notify bool      `query:"notify"`
+	Tags   []string  `query:"tag"`
+	Since  time.Time `query:"since"`
+}
+
+func (u *updateUser) Validate() error {
+	if u.Name == "" {
+		return errors.New("name is required")
+	}
+	return nil
+}
+
+// TestBindAll verifies that path, query and body are bound with path over
+// query over body precedence, followed by validation
+func TestBindAll(t *testing.T) {
+	var got updateUser
+	var bindErr error
+	engine := New()
+	engine.PUT("/orgs/:org/users/:id", func(c *Context) {
+		got = updateUser{}
+		bindErr = c.BindAll(&got)
+	})
+
+	put := func(target, body string) {
+		var r *http.Request
+		if body == "" {
+			r = httptest.NewRequest(http.MethodPut, target, nil)
+		} else {
+			r = httptest.NewRequest(http.MethodPut, target, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+		}
+		engine.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	target := "/orgs/acme/users/42?org=other&notify=true&tag=a&tag=b&since=2024-01-02T03:04:05Z"
+	body := `{"id": 7, "org": "body", "name": "Ada"}`
+	put(target, body)
+	if bindErr != nil {
+		t.Fatalf("Expected no error, got %v", bindErr)
+	}
+	if got.ID != 42 || got.Org != "acme" {
+		t.Errorf("Expected path to take precedence, got id=%d org=%q", got.ID, got.Org)
+	}
+	if got.Name != "Ada" || !got.Notify {
+		t.Errorf("Expected name from body and notify from query, got %q %v", got.Name, got.Notify)
+	}
+	if len(got.Tags) != 2 || got.Tags[0] != "a" || got.Tags[1] != "b" {
+		t.Errorf("Expected repeated tags [a b], got %v", got.Tags)
+	}
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !got.Since.Equal(want) {
+		t.Errorf("Expected since %v, got %v", want, got.Since)
+	}
+
+	put("/orgs/acme/users/42", "")
+	var he *HTTPError
+	if !errors.As(bindErr, &he) || he.Status != http.StatusBadRequest || he.Message != "name is required" {
+		t.Errorf("Expected validation error as 400, got %v", bindErr)
+	} else if errors.Unwrap(bindErr) == nil {
+		t.Errorf("Expected the validation error to be the cause")
+	}
+
+	put("/orgs/acme/users/42?notify=maybe", body)
+	if bindErr == nil || !strings.Contains(bindErr.Error(), `query parameter "notify"`) {
+		t.Errorf("Expected query parse error, got %v", bindErr)
+	}
+
+	put("/orgs/acme/users/42", "{")
+	if bindErr == nil || !strings.Contains(bindErr.Error(), "malformed JSON") {
+		t.Errorf("Expected body decode error, got %v", bindErr)
+	}
+}
+
+// TestBindAllErrorStatus verifies that binding and validation failures are
+// rendered as 400 by Context.Error, with the parse error still reachable
+func TestBindAllErrorStatus(t *testing.T) {
+	var bindErr error
+	engine := New()
+	engine.PUT("/orgs/:org/users/:id", func(c *Context) {
+		var u updateUser
+		bindErr = c.BindAll(&u)
+		if bindErr != nil {
+			c.Error(bindErr)
+		}
+	})
+
+	tests := []struct {
+		target string
+		body   string
+		status int
+	}{
+		{"/orgs/acme/users/x", `{"name": "Ada"}`, http.StatusBadRequest},
+		{"/orgs/acme/users/42?since=yesterday", `{"name": "Ada"}`, http.StatusBadRequest},
+		{"/orgs/acme/users/42", `{}`, http.StatusBadRequest},
+		{"/orgs/acme/users/42", `{"name": "Ada"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPut, tt.target, strings.NewReader(tt.body))
+		r.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, r)
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.target, tt.body, tt.status, rr.Code)
+		}
+	}
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/orgs/acme/users/x", nil))
+	var numErr *strconv.NumError
+	if !errors.As(bindErr, &numErr) {
+		t.Errorf("Expected the parse error to be reachable, got %v", bindErr)
+	}
+}
+
+// TestBindPath verifies that path parameters are bound and parse errors reported
+func TestBindPath(t *testing.T) {
+	var bindErr error
+	var got struct {
+		ID   uint   `uri:"id"`
+		Slug string `uri:"slug"`
+	}
+	engine := New()
+	engine.GET("/posts/:id", func(c *Context) {
+		bindErr = c.BindPath(&got)
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts/9", nil))
+	if bindErr != nil || got.ID != 9 || got.Slug != "" {
+		t.Errorf("Expected id=9 and empty slug, got %+v (%v)", got, bindErr)
+	}
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts/-1", nil))
+	if bindErr == nil || !strings.Contains(bindErr.Error(), `path parameter "id"`) {
+		t.Errorf("Expected path parse error, got %v", bindErr)
+	}
+
+	engine.GET("/bad/:id", func(c *Context) {
+		bindErr = c.BindPath(got)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/bad/1", nil))
+	if bindErr == nil {
+		t.Errorf("Expected error for non-pointer target")
+	}
+}
+
+// TestBindJSON verifies content type enforcement, size limits, unknown
+// field rejection and descriptive decoding errors
+func TestBindJSON(t *testing.T) {
+	type item struct {
+		Name  string `json:"name"`
+		Price int    `json:"price"`
+	}
+
+	config := DefaultConfig()
+	config.MaxJSONBodySize = 64
+	config.DisallowUnknownJSONFields = true
+	engine := NewWithConfig(config)
+	engine.POST("/items", func(c *Context) {
+		var it item
+		if err := c.BindJSON(&it); err != nil {
+			c.Error(err)
+			return
+		}
+		c.String(http.StatusCreated, "%s %d", it.Name, it.Price)
+	})
+
+	tests := []struct {
+		contentType string
+		body        string
+		status      int
+		message     string
+	}{
+		{"application/json", `{"name":"pen","price":3}`, http.StatusCreated, "pen 3"},
+		{"application/json; charset=utf-8", `{"name":"ink"}`, http.StatusCreated, "ink 0"},
+		{"application/merge-patch+json", `{"price":5}`, http.StatusCreated, " 5"},
+		{"text/plain", `{"name":"pen"}`, http.StatusUnsupportedMediaType, "Content-Type must be application/json\n"},
+		{"", `{"name":"pen"}`, http.StatusUnsupportedMediaType, "Content-Type must be application/json\n"},
+		{"application/json", `{"name":"` + strings.Repeat("x", 64) + `"}`, http.StatusRequestEntityTooLarge, "request body exceeds 64 bytes\n"},
+		{"application/json", `{"name":"pen",}`, http.StatusBadRequest, "malformed JSON at offset 15: invalid character '}' looking for beginning of object key string\n"},
+		{"application/json", `{"price":"3"}`, http.StatusBadRequest, "invalid value for field \"price\" at offset 12: expected int, got JSON string\n"},
+		{"application/json", `{"name":"pen","colour":"red"}`, http.StatusBadRequest, "unknown field \"colour\"\n"},
+		{"application/json", `{"name":"pen"} {}`, http.StatusBadRequest, "request body must hold a single JSON value\n"},
+		{"application/json", `{"name":`, http.StatusBadRequest, "malformed JSON: unexpected end of body\n"},
+		{"application/json", ``, http.StatusBadRequest, "request body is empty\n"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s %q: expected status %d, got %d", tt.contentType, tt.body, tt.status, rr.Code)
+		}
+		if rr.Body.String() != tt.message {
+			t.Errorf("%s %q: expected body %q, got %q", tt.contentType, tt.body, tt.message, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package goexpress
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+)
+
+// BodyTransform wraps the reader of a request body, for example to
+// decompress or decrypt it. It may inspect the request headers to decide
+// whether to act; returning body unchanged skips the transform. A returned
+// error rejects the request with 400 Bad Request; the error is logged but
+// not sent to the client, as it may reveal internal details. If the returned reader
+// implements io.Closer it is closed when the request body is closed.
+type BodyTransform func(r *http.Request, body io.Reader) (io.Reader, error)
+
+// UseBodyTransform appends t to the body transform pipeline. Transforms run
+// in registration order before the request is dispatched: the first one
+// wraps the raw body and every later one wraps the output of the previous
+// one, so registering decompress, then decrypt, then decode reads the wire
+// bytes through each step in that order. Transforms of a mounted engine run
+// after those of the engine it is mounted on. Config.MaxTransformedBodySize
+// caps the number of bytes handlers can read from the final reader.
+func (e *Engine) UseBodyTransform(t BodyTransform) {
+	e.bodyTransforms = append(e.bodyTransforms, t)
+}
+
+// transformedBody is the request body after all transforms have been applied.
+type transformedBody struct {
+	io.Reader
+	closers []io.Closer
+}
+
+// Close closes the transform readers from the outermost inwards, then the
+// original body, and returns their errors joined.
+func (b *transformedBody) Close() error {
+	var errs []error
+	for i := len(b.closers) - 1; i >= 0; i-- {
+		errs = append(errs, b.closers[i].Close())
+	}
+	return errors.Join(errs...)
+}
+
+// applyBodyTransforms replaces r.Body with the output of the transform
+// pipeline. It reports false, after writing 400 Bad Request, if a transform
+// rejects the body.
+func (e *Engine) applyBodyTransforms(w http.ResponseWriter, r *http.Request) bool {
+	if len(e.bodyTransforms) == 0 || r.Body == nil || r.Body == http.NoBody {
+		return true
+	}
+
+	body := &transformedBody{Reader: r.Body, closers: []io.Closer{r.Body}}
+	for _, t := range e.bodyTransforms {
+		next, err := t(r, body.Reader)
+		if err != nil {
+			body.Close()
+			log.Printf("[BodyTransform] rejected %s %s from %s: %v\n", r.Method, r.URL.Path, r.RemoteAddr, err)
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return false
+		}
+		if c, ok := next.(io.Closer); ok && next != body.Reader {
+			body.closers = append(body.closers, c)
+		}
+		body.Reader = next
+	}
+
+	if max := e.config.MaxTransformedBodySize; max > 0 {
+		r.Body = http.MaxBytesReader(w, body, max)
+	} else {
+		r.Body = body
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package goexpress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// gunzipTransform decompresses gzip-encoded request bodies.
+func gunzipTransform(r *http.Request, body io.Reader) (io.Reader, error) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return body, nil
+	}
+	return gzip.NewReader(body)
+}
+
+// upperTransform upper-cases the body to make transform ordering observable.
+func upperTransform(r *http.Request, body io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(bytes.ToUpper(data)), nil
+}
+
+// gzipBody compresses s for use as a request body.
+func gzipBody(t *testing.T, s string) io.Reader {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	zw.Close()
+	return &buf
+}
+
+// TestBodyTransformOrder verifies that transforms are applied in registration order
+func TestBodyTransformOrder(t *testing.T) {
+	engine := New()
+	engine.UseBodyTransform(gunzipTransform)
+	engine.UseBodyTransform(upperTransform)
+
+	req := httptest.NewRequest(http.MethodPost, "/", gzipBody(t, "hello"))
+	req.Header.Set("Content-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	if !engine.applyBodyTransforms(rr, req) {
+		t.Fatalf("Expected transforms to succeed, got status %d", rr.Code)
+	}
+
+	data, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("Failed to read transformed body: %v", err)
+	}
+	if string(data) != "HELLO" {
+		t.Errorf("Expected body HELLO, got %q", data)
+	}
+	if err := req.Body.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
+
+// TestBodyTransformError verifies that a failing transform rejects the
+// request with 400 and logs the cause without sending it to the client
+func TestBodyTransformError(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	engine := New()
+	engine.UseBodyTransform(gunzipTransform)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rr.Code)
+	}
+	if rr.Body.String() != "invalid request body\n" {
+		t.Errorf("Expected a generic message, got %q", rr.Body.String())
+	}
+	if !strings.Contains(buf.String(), "[BodyTransform] rejected POST /") || !strings.Contains(buf.String(), "unexpected EOF") {
+		t.Errorf("Expected the cause to be logged, got %q", buf.String())
+	}
+}
+
+// TestBodyTransformSizeLimit verifies that the transformed body is capped
+func TestBodyTransformSizeLimit(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxTransformedBodySize = 10
+	engine := NewWithConfig(config)
+	engine.UseBodyTransform(gunzipTransform)
+
+	req := httptest.NewRequest(http.MethodPost, "/", gzipBody(t, strings.Repeat("a", 1000)))
+	req.Header.Set("Content-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	if !engine.applyBodyTransforms(rr, req) {
+		t.Fatalf("Expected transforms to succeed, got status %d", rr.Code)
+	}
+
+	_, err := io.ReadAll(req.Body)
+	var maxErr *http.MaxBytesError
+	if !errors.As(err, &maxErr) {
+		t.Errorf("Expected *http.MaxBytesError, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package goexpress
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+)
+
+// buildInfo is the JSON document served by EnableBuildInfo.
+type buildInfo struct {
+	Version      string       `json:"version"`
+	GoVersion    string       `json:"go_version"`
+	Module       string       `json:"module,omitempty"`
+	VCSRevision  string       `json:"vcs_revision,omitempty"`
+	VCSTime      string       `json:"vcs_time,omitempty"`
+	VCSModified  bool         `json:"vcs_modified,omitempty"`
+	Dependencies []dependency `json:"dependencies,omitempty"`
+}
+
+type dependency struct {
+	Path    string `json:"path"`
+	Version string `json:"version"`
+}
+
+// EnableBuildInfo registers a GET route at path that reports Config.Version,
+// the Go version, the main module, the VCS revision the binary was built
+// from and its module dependencies as JSON. The middleware, if any, wraps
+// the route so access can be restricted, for example to internal callers.
+func (e *Engine) EnableBuildInfo(path string, middleware ...Middleware) {
+	info := buildInfo{
+		Version:   e.config.Version,
+		GoVersion: runtime.Version(),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.Module = bi.Main.Path
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.VCSRevision = s.Value
+			case "vcs.time":
+				info.VCSTime = s.Value
+			case "vcs.modified":
+				info.VCSModified = s.Value == "true"
+			}
+		}
+		for _, dep := range bi.Deps {
+			info.Dependencies = append(info.Dependencies, dependency{Path: dep.Path, Version: dep.Version})
+		}
+	}
+
+	body, err := json.Marshal(info)
+	if err != nil {
+		panic("goexpress: encoding build info: " + err.Error())
+	}
+	e.GET(path, chain(func(c *Context) {
+		c.Writer.Header().Set("Content-Type", "application/json")
+		c.Writer.WriteHeader(http.StatusOK)
+		c.Writer.Write(body)
+	}, middleware))
+}
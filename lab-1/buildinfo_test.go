@@ -0,0 +1,56 @@
+package goexpress
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+// TestEnableBuildInfo verifies that the build info route reports the
+// configured version and Go version, and honours its middleware
+func TestEnableBuildInfo(t *testing.T) {
+	config := DefaultConfig()
+	config.Version = "1.2.3"
+	engine := NewWithConfig(config)
+
+	internalOnly := func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if c.Request.Header.Get("X-Internal") == "" {
+				c.Writer.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next(c)
+		}
+	}
+	engine.EnableBuildInfo("/buildinfo", internalOnly)
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/buildinfo", nil))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 without the internal header, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/buildinfo", nil)
+	req.Header.Set("X-Internal", "1")
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", ct)
+	}
+
+	var info buildInfo
+	if err := json.Unmarshal(rr.Body.Bytes(), &info); err != nil {
+		t.Fatalf("Invalid JSON body: %v", err)
+	}
+	if info.Version != "1.2.3" {
+		t.Errorf("Expected version 1.2.3, got %q", info.Version)
+	}
+	if info.GoVersion != runtime.Version() {
+		t.Errorf("Expected Go version %s, got %q", runtime.Version(), info.GoVersion)
+	}
+}
@@ -0,0 +1,86 @@
+package goexpress
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"strconv"
+	"sync"
+)
+
+// maxCaptureBodySize is the largest request body EnableCapture records.
+const maxCaptureBodySize = 64 << 10
+
+// capture writes incoming requests to w, see EnableCapture.
+type capture struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// EnableCapture writes every incoming request, with its method, URL,
+// headers and body, to w in HTTP/1.1 wire format, before any check or
+// handler runs. The output is a plain stream of requests that can be read
+// back one by one with http.ReadRequest and served again, for example
+// against a local build, to reproduce exactly the traffic that triggered a
+// bug. Requests with a body over 64 KB are skipped and logged, so capture
+// cannot buffer large uploads in memory. Captures include credentials such
+// as Cookie and Authorization headers, so EnableCapture only takes effect
+// when Config.Debug is set; otherwise it logs a warning and does nothing.
+// It must not be called while the engine is serving requests.
+func (e *Engine) EnableCapture(w io.Writer) {
+	if !e.config.Debug {
+		log.Println("[Capture] EnableCapture ignored: Config.Debug is not set")
+		return
+	}
+	e.capture = &capture{w: w}
+}
+
+// record writes r to the capture output, restoring r.Body so the request
+// can still be served.
+func (cp *capture) record(r *http.Request) {
+	var body []byte
+	if r.Body != nil && r.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(io.LimitReader(r.Body, maxCaptureBodySize+1))
+		r.Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(body), r.Body), Closer: r.Body}
+		if err != nil {
+			log.Printf("[Capture] skipped %s %s: %v\n", r.Method, r.URL.RequestURI(), err)
+			return
+		}
+		if len(body) > maxCaptureBodySize {
+			log.Printf("[Capture] skipped %s %s: body exceeds %d bytes\n", r.Method, r.URL.RequestURI(), maxCaptureBodySize)
+			return
+		}
+	}
+
+	// Dump a copy with the buffered body and an explicit length, so that
+	// chunked requests are written in a form http.ReadRequest reads back
+	dump := *r
+	dump.Header = r.Header.Clone()
+	dump.TransferEncoding = nil
+	dump.ContentLength = int64(len(body))
+	dump.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > 0 {
+		dump.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	out, err := httputil.DumpRequest(&dump, true)
+	if err != nil {
+		log.Printf("[Capture] skipped %s %s: %v\n", r.Method, r.URL.RequestURI(), err)
+		return
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if _, err := cp.w.Write(out); err != nil {
+		log.Printf("[Capture] write failed: %v\n", err)
+	}
+}
+
+// replayBody serves the part of a request body read for capture followed
+// by the rest, and closes the original body.
+type replayBody struct {
+	io.Reader
+	io.Closer
+}
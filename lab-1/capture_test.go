@@ -0,0 +1,83 @@
+package goexpress
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestEnableCapture verifies that requests are captured in a replayable
+// format while still reaching their handlers, and that oversized bodies
+// are skipped
+func TestEnableCapture(t *testing.T) {
+	var out bytes.Buffer
+	config := DefaultConfig()
+	config.Debug = true
+	engine := NewWithConfig(config)
+	engine.EnableCapture(&out)
+	engine.POST("/orders", func(c *Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Writer.Write(body)
+	})
+
+	requests := []struct {
+		target string
+		body   string
+	}{
+		{"/orders?dry_run=1", `{"item":"pen"}`},
+		{"/orders", strings.Repeat("x", maxCaptureBodySize+1)},
+		{"/orders", "second"},
+	}
+	for _, rq := range requests {
+		req := httptest.NewRequest(http.MethodPost, rq.target, strings.NewReader(rq.body))
+		req.Header.Set("X-Trace", "abc")
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Body.String() != rq.body {
+			t.Errorf("%s: expected handler to read the full body, got %d bytes", rq.target, rr.Body.Len())
+		}
+	}
+
+	want := []struct {
+		uri  string
+		body string
+	}{
+		{"/orders?dry_run=1", `{"item":"pen"}`},
+		{"/orders", "second"},
+	}
+	br := bufio.NewReader(&out)
+	for _, w := range want {
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			t.Fatalf("Expected captured request %s, got error: %v", w.uri, err)
+		}
+		body, _ := io.ReadAll(req.Body)
+		if req.Method != http.MethodPost || req.RequestURI != w.uri || string(body) != w.body {
+			t.Errorf("Expected POST %s %q, got %s %s %q", w.uri, w.body, req.Method, req.RequestURI, body)
+		}
+		if got := req.Header.Get("X-Trace"); got != "abc" {
+			t.Errorf("Expected captured header X-Trace abc, got %q", got)
+		}
+	}
+	if _, err := http.ReadRequest(br); err != io.EOF {
+		t.Errorf("Expected no further captured requests, got %v", err)
+	}
+}
+
+// TestEnableCaptureRequiresDebug verifies that capture is ignored outside
+// debug mode
+func TestEnableCaptureRequiresDebug(t *testing.T) {
+	var out bytes.Buffer
+	engine := New()
+	engine.EnableCapture(&out)
+	engine.GET("/", echoHandler)
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing captured without Debug, got %q", out.String())
+	}
+}
@@ -0,0 +1,189 @@
+package goexpress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// CompressConfig configures the Compress middleware.
+type CompressConfig struct {
+	// Level is the compression level, from gzip.HuffmanOnly to
+	// gzip.BestCompression. gzip.BestSpeed suits CPU-bound services and
+	// gzip.BestCompression bandwidth-bound ones. The zero value selects
+	// gzip.DefaultCompression
+	Level int
+
+	// Encodings lists the content codings to offer in order of
+	// preference, out of "gzip" and "deflate". Defaults to both, gzip first
+	Encodings []string
+}
+
+// encoder is the common interface of *gzip.Writer and *flate.Writer.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compress returns a middleware that compresses response bodies with the
+// first of config.Encodings the client accepts in Accept-Encoding, taking
+// its quality values into account. Responses to HEAD requests, responses
+// without a body and responses whose handler set Content-Encoding itself
+// are left as they are. Brotli is not offered, as it would require a
+// dependency outside the standard library. It panics if the level or an
+// encoding is invalid.
+func Compress(config CompressConfig) Middleware {
+	level := config.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		panic("goexpress: invalid compression level: " + strconv.Itoa(level))
+	}
+	encodings := config.Encodings
+	if len(encodings) == 0 {
+		encodings = []string{"gzip", "deflate"}
+	}
+
+	pools := make(map[string]*sync.Pool, len(encodings))
+	for _, name := range encodings {
+		var newEncoder func() any
+		switch name {
+		case "gzip":
+			newEncoder = func() any {
+				enc, _ := gzip.NewWriterLevel(io.Discard, level)
+				return enc
+			}
+		case "deflate":
+			newEncoder = func() any {
+				enc, _ := flate.NewWriter(io.Discard, level)
+				return enc
+			}
+		default:
+			panic("goexpress: unsupported compression encoding: " + name)
+		}
+		pools[name] = &sync.Pool{New: newEncoder}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			c.Writer.Header().Add("Vary", "Accept-Encoding")
+			name := negotiateEncoding(c.Request.Header.Get("Accept-Encoding"), encodings)
+			if name == "" || c.Request.Method == http.MethodHead {
+				next(c)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: c.Writer, encoding: name, pool: pools[name]}
+			c.Writer = cw
+			defer func() {
+				cw.close()
+				c.Writer = cw.ResponseWriter
+			}()
+			next(c)
+		}
+	}
+}
+
+// negotiateEncoding returns the first of offered with the highest nonzero
+// quality in the Accept-Encoding header, or "" if none is acceptable.
+func negotiateEncoding(header string, offered []string) string {
+	if header == "" {
+		return ""
+	}
+
+	quality := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		quality[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, name := range offered {
+		q, ok := quality[name]
+		if !ok {
+			q = quality["*"]
+		}
+		if q > bestQ {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter compresses the response body once the status is known
+// to allow one.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	pool     *sync.Pool
+	enc      encoder
+	decided  bool
+}
+
+// WriteHeader switches to compressed output unless status carries no body
+// or the handler chose its own Content-Encoding.
+func (w *compressWriter) WriteHeader(status int) {
+	if !w.decided {
+		w.decided = true
+		h := w.Header()
+		if bodyAllowed(status) && h.Get("Content-Encoding") == "" {
+			h.Set("Content-Encoding", w.encoding)
+			h.Del("Content-Length")
+			w.enc = w.pool.Get().(encoder)
+			w.enc.Reset(w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses b, sending a 200 status first if none was written.
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.enc == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.enc.Write(b)
+}
+
+// Flush sends the data compressed so far to the client.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.enc != nil {
+		w.enc.Flush()
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// close finishes the compressed stream and returns the encoder to its pool.
+func (w *compressWriter) close() {
+	if w.enc == nil {
+		return
+	}
+	w.enc.Close()
+	w.enc.Reset(io.Discard)
+	w.pool.Put(w.enc)
+	w.enc = nil
+}
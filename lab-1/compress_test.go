@@ -0,0 +1,125 @@
+package goexpress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCompress verifies that response bodies are compressed with the
+// preferred encoding the client accepts
+func TestCompress(t *testing.T) {
+	body := strings.Repeat("compress me ", 100)
+	engine := New()
+	engine.Use(Compress(CompressConfig{Level: gzip.BestSpeed}))
+	engine.GET("/", func(c *Context) {
+		c.String(http.StatusOK, "%s", body)
+	})
+
+	tests := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"gzip;q=0, br", ""},
+		{"*", "gzip"},
+		{"identity", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+		}
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+
+		if got := rr.Header().Get("Content-Encoding"); got != tt.encoding {
+			t.Errorf("%q: expected Content-Encoding %q, got %q", tt.acceptEncoding, tt.encoding, got)
+			continue
+		}
+		if got := rr.Header().Get("Vary"); got != "Accept-Encoding" {
+			t.Errorf("%q: expected Vary Accept-Encoding, got %q", tt.acceptEncoding, got)
+		}
+
+		var r io.Reader = rr.Body
+		switch tt.encoding {
+		case "gzip":
+			if rr.Header().Get("Content-Length") != "" {
+				t.Errorf("%q: expected Content-Length to be removed", tt.acceptEncoding)
+			}
+			zr, err := gzip.NewReader(rr.Body)
+			if err != nil {
+				t.Fatalf("%q: invalid gzip stream: %v", tt.acceptEncoding, err)
+			}
+			r = zr
+		case "deflate":
+			r = flate.NewReader(rr.Body)
+		}
+		got, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%q: failed to decode body: %v", tt.acceptEncoding, err)
+		}
+		if string(got) != body {
+			t.Errorf("%q: body did not round-trip, got %d bytes", tt.acceptEncoding, len(got))
+		}
+	}
+}
+
+// TestCompressSkipped verifies that bodiless responses and responses with
+// their own Content-Encoding are passed through untouched
+func TestCompressSkipped(t *testing.T) {
+	engine := New()
+	engine.Use(Compress(CompressConfig{}))
+	engine.DELETE("/items/1", func(c *Context) {
+		c.NoContent()
+	})
+	engine.GET("/raw", func(c *Context) {
+		c.Writer.Header().Set("Content-Encoding", "identity")
+		c.Writer.Write([]byte("raw"))
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/items/1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent || rr.Header().Get("Content-Encoding") != "" || rr.Body.Len() != 0 {
+		t.Errorf("Expected uncompressed 204, got %d %q with %d body bytes",
+			rr.Code, rr.Header().Get("Content-Encoding"), rr.Body.Len())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/raw", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+	if rr.Header().Get("Content-Encoding") != "identity" || rr.Body.String() != "raw" {
+		t.Errorf("Expected handler encoding to be kept, got %q %q",
+			rr.Header().Get("Content-Encoding"), rr.Body.String())
+	}
+}
+
+// TestCompressInvalidConfig verifies that invalid levels and encodings panic
+func TestCompressInvalidConfig(t *testing.T) {
+	configs := []CompressConfig{
+		{Level: gzip.BestCompression + 1},
+		{Level: gzip.HuffmanOnly - 1},
+		{Encodings: []string{"br"}},
+	}
+	for _, config := range configs {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for %+v", config)
+				}
+			}()
+			Compress(config)
+		}()
+	}
+}
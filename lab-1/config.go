@@ -1,9 +1,21 @@
 package goexpress
 
-import "time"
+import (
+	"crypto/tls"
+	"net"
+	"time"
+)
 
 // Config holds all configuration for the HTTP server
 type Config struct {
+	// Version is the application version reported by EnableBuildInfo
+	Version string
+
+	// Debug enables development aids, such as EnableCapture and
+	// SafeMethodGuard, that may expose sensitive data or cost performance.
+	// Never set it in production
+	Debug bool
+
 	// Port is the address and port to listen on
 	Port string
 
@@ -12,13 +24,100 @@ type Config struct {
 
 	// WriteTimeout is the maximum duration before timing out writes of the response
 	WriteTimeout time.Duration
+
+	// ReadHeaderTimeout is the maximum duration for reading the request
+	// headers, which stops slowloris clients that trickle them. Zero falls
+	// back to ReadTimeout, and no timeout if both are zero
+	ReadHeaderTimeout time.Duration
+
+	// IdleTimeout is how long a keep-alive connection may sit idle waiting
+	// for the next request. Zero falls back to ReadTimeout, and no timeout
+	// if both are zero
+	IdleTimeout time.Duration
+
+	// MaxHeaderBytes is the maximum size of the request line and headers.
+	// Larger requests are rejected by net/http with 431 Request Header
+	// Fields Too Large. Zero uses the net/http default, http.DefaultMaxHeaderBytes
+	MaxHeaderBytes int
+
+	// TLSConfig, if set, configures the TLS server used by RunTLS, for
+	// example to require client certificates for mutual TLS or to restrict
+	// cipher suites
+	TLSConfig *tls.Config
+
+	// ReusePort sets SO_REUSEPORT on the listening socket so that several
+	// processes can bind the same port and share incoming connections.
+	// It is only supported on Linux, macOS and the BSDs; on other platforms
+	// Run returns an error when it is enabled
+	ReusePort bool
+
+	// ListenConfig, if set, is used by Run to create the listening socket,
+	// giving control over keep-alive settings and socket options through
+	// its Control callback. With ReusePort, SO_REUSEPORT is set after the
+	// callback runs
+	ListenConfig *net.ListenConfig
+
+	// ConnFilter, if set, is called for every accepted connection before
+	// any HTTP parsing; connections for which it returns false are closed
+	// immediately. Filtering here, typically on c.RemoteAddr(), costs no
+	// request parsing, goroutine or middleware work for blocked clients,
+	// which makes it far cheaper than rejecting them in a handler when
+	// under attack. It runs on the accept loop and must be fast
+	ConnFilter func(c net.Conn) bool
+
+	// MaxURILength is the maximum length of the request path and query.
+	// Longer requests are rejected with 414 URI Too Long before routing.
+	// Zero means no limit
+	MaxURILength int
+
+	// AllowedHosts lists the host names the server answers to, without port.
+	// Requests for any other host are rejected with 421 Misdirected Request.
+	// Requests with a malformed Host header are always rejected with 400.
+	// An empty list allows every well-formed host
+	AllowedHosts []string
+
+	// MaxConnRequests limits how many requests a single client connection may
+	// have in flight at once; extra requests get 503 Service Unavailable.
+	// HTTP/1.1 serves one request per connection at a time (pipelined requests
+	// are queued by net/http), so this mainly bounds HTTP/2 multiplexing.
+	// The framework has no global concurrency cap, so this limit applies on
+	// its own. Zero means no limit
+	MaxConnRequests int
+
+	// MaxTransformedBodySize is the maximum number of bytes handlers may read
+	// from a request body after the UseBodyTransform pipeline, guarding
+	// against decompression bombs. Zero means no limit
+	MaxTransformedBodySize int64
+
+	// MaxJSONBodySize is the maximum number of bytes Context.BindJSON reads
+	// from a request body; larger bodies are rejected with 413 Request
+	// Entity Too Large. Zero means 1 MB
+	MaxJSONBodySize int64
+
+	// DisallowUnknownJSONFields makes Context.BindJSON reject objects with
+	// keys that match no field of the target, catching typos in clients
+	DisallowUnknownJSONFields bool
+
+	// PreStopDelay is how long Shutdown keeps serving new requests before it
+	// starts draining. On Kubernetes, a pod gets SIGTERM while it may still
+	// be listed as an endpoint, so a delay of a few seconds lets the control
+	// plane stop routing traffic to it first. Zero starts draining at once
+	PreStopDelay time.Duration
+
+	// ShutdownLogInterval is how often Shutdown logs the number of in-flight
+	// requests and open connections while it waits for them to drain.
+	// Zero disables the progress log
+	ShutdownLogInterval time.Duration
 }
 
 // DefaultConfig returns a Config with sensible default values
 func DefaultConfig() *Config {
 	return &Config{
-		Port:         ":8080",
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+		Port:              ":8080",
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		ReadHeaderTimeout: 5 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		MaxHeaderBytes:    1 << 20,
 	}
 }
@@ -0,0 +1,73 @@
+package goexpress
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+// connStateKey is the context key under which each connection's state is stored.
+type connStateKey struct{}
+
+// connState tracks per-connection bookkeeping shared by all requests
+// arriving on the same connection.
+type connState struct {
+	inflight atomic.Int32
+}
+
+// connContext is installed as http.Server.ConnContext and attaches a fresh
+// connState to the base context of every accepted connection.
+func connContext(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connStateKey{}, &connState{})
+}
+
+// acquireConnSlot reserves an in-flight slot on the request's connection.
+// It reports false, after writing 503 Service Unavailable, if the connection
+// already has Config.MaxConnRequests requests in flight. On success the
+// returned function must be called to release the slot.
+func (e *Engine) acquireConnSlot(w http.ResponseWriter, r *http.Request) (func(), bool) {
+	max := e.config.MaxConnRequests
+	state, ok := r.Context().Value(connStateKey{}).(*connState)
+	if max <= 0 || !ok {
+		return func() {}, true
+	}
+
+	if state.inflight.Add(1) > int32(max) {
+		state.inflight.Add(-1)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return func() { state.inflight.Add(-1) }, true
+}
+
+// trackConn is installed as http.Server.ConnState and keeps the count of
+// open connections up to date.
+func (e *Engine) trackConn(c net.Conn, state http.ConnState) {
+	switch state {
+	case http.StateNew:
+		e.conns.Add(1)
+	case http.StateClosed, http.StateHijacked:
+		e.conns.Add(-1)
+	}
+}
+
+// filterListener closes accepted connections rejected by filter before
+// net/http reads anything from them.
+type filterListener struct {
+	net.Listener
+	filter func(net.Conn) bool
+}
+
+func (l *filterListener) Accept() (net.Conn, error) {
+	for {
+		c, err := l.Listener.Accept()
+		if err != nil {
+			return nil, err
+		}
+		if l.filter(c) {
+			return c, nil
+		}
+		c.Close()
+	}
+}
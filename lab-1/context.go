@@ -0,0 +1,118 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Context carries the request and response of a single HTTP exchange.
+// It is the only argument handlers receive. Contexts are pooled and reused
+// for later requests once the handler chain returns, so a Context, or its
+// Writer, must not be kept or used after that, for example by a goroutine
+// started in the handler; copy the values needed instead.
+type Context struct {
+	// Writer is the response writer for the request.
+	Writer http.ResponseWriter
+
+	// Request is the incoming HTTP request.
+	Request *http.Request
+
+	engine *Engine
+	writer responseWriter
+	params *params
+	// query caches the parsed URL query, see queryValues.
+	query url.Values
+}
+
+// newContext returns a Context for w and r served by e with the matched
+// route params.
+func newContext(e *Engine, w http.ResponseWriter, r *http.Request, ps *params) *Context {
+	c := &Context{}
+	c.reset(e, w, r, ps)
+	return c
+}
+
+// reset prepares c, which may have served an earlier request, for w and r.
+// Every field is overwritten so that nothing leaks between requests.
+func (c *Context) reset(e *Engine, w http.ResponseWriter, r *http.Request, ps *params) {
+	c.Request = r
+	c.engine = e
+	c.params = ps
+	c.query = nil
+	c.writer.reset(w)
+	c.Writer = &c.writer
+}
+
+// derive returns a Context for the same matched route that writes to w
+// and serves r, for middleware that runs the rest of the chain with a
+// different writer or request.
+func (c *Context) derive(w http.ResponseWriter, r *http.Request) *Context {
+	d := newContext(c.engine, w, r, c.params)
+	d.writer.contentType = c.writer.contentType
+	d.query = c.query
+	return d
+}
+
+// Param returns the value of the named path parameter captured by the
+// matched route, such as "42" for ":id" in "/users/:id" when serving
+// "/users/42", or "a/b" for "*path" in "/files/*path" when serving
+// "/files/a/b". It returns an empty string if the parameter does not exist.
+func (c *Context) Param(name string) string {
+	return c.params.get(name)
+}
+
+// Status returns the response status written so far, or 200 if the
+// handler has not written one yet.
+func (c *Context) Status() int {
+	return c.writer.status
+}
+
+// ServerClosing returns the channel of Engine.ServerClosing, which is
+// closed as soon as Shutdown begins.
+func (c *Context) ServerClosing() <-chan struct{} {
+	return c.engine.ServerClosing()
+}
+
+// Query returns the first value of the named URL query parameter, or an
+// empty string if it is not present.
+func (c *Context) Query(name string) string {
+	return c.queryValues().Get(name)
+}
+
+// QueryDefault returns the first value of the named URL query parameter,
+// or fallback if it is not present. A parameter given with an empty value,
+// as in "?q=", is present and yields "".
+func (c *Context) QueryDefault(name, fallback string) string {
+	if values, ok := c.queryValues()[name]; ok {
+		return values[0]
+	}
+	return fallback
+}
+
+// QueryInt returns the first value of the named URL query parameter as a
+// decimal integer. It returns 0 and no error if the parameter is not
+// present, and a *strconv.NumError if its value is not an integer.
+func (c *Context) QueryInt(name string) (int, error) {
+	values, ok := c.queryValues()[name]
+	if !ok {
+		return 0, nil
+	}
+	return strconv.Atoi(values[0])
+}
+
+// QueryArray returns every value of the named URL query parameter in
+// order, such as ["a", "b"] for "?tag=a&tag=b", or nil if it is not
+// present. The returned slice must not be modified.
+func (c *Context) QueryArray(name string) []string {
+	return c.queryValues()[name]
+}
+
+// queryValues returns the URL query, parsing it on first use so that
+// repeated lookups in a request share one parse.
+func (c *Context) queryValues() url.Values {
+	if c.query == nil {
+		c.query = c.Request.URL.Query()
+	}
+	return c.query
+}
@@ -0,0 +1,118 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestContextAccessors verifies that handlers receive a Context exposing
+// the request, the writer, path params and query values
+func TestContextAccessors(t *testing.T) {
+	engine := New()
+	engine.GET("/users/:id", func(c *Context) {
+		if c.Request.Method != http.MethodGet {
+			t.Errorf("Expected request method GET, got %s", c.Request.Method)
+		}
+		if got := c.Param("id"); got != "42" {
+			t.Errorf("Expected param id=42, got %q", got)
+		}
+		if got := c.Param("missing"); got != "" {
+			t.Errorf("Expected empty missing param, got %q", got)
+		}
+		if got := c.Query("sort"); got != "name" {
+			t.Errorf("Expected query sort=name, got %q", got)
+		}
+		if got := c.Query("missing"); got != "" {
+			t.Errorf("Expected empty missing query, got %q", got)
+		}
+		c.Writer.WriteHeader(http.StatusAccepted)
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users/42?sort=name", nil))
+	if rr.Code != http.StatusAccepted {
+		t.Errorf("Expected status 202, got %d", rr.Code)
+	}
+}
+
+// TestQueryHelpers verifies defaults, integer parsing and repeated values
+// of query parameters
+func TestQueryHelpers(t *testing.T) {
+	engine := New()
+	engine.GET("/items", func(c *Context) {
+		if got := c.QueryDefault("sort", "name"); got != "price" {
+			t.Errorf("Expected sort=price, got %q", got)
+		}
+		if got := c.QueryDefault("order", "asc"); got != "asc" {
+			t.Errorf("Expected fallback order=asc, got %q", got)
+		}
+		if got := c.QueryDefault("q", "all"); got != "" {
+			t.Errorf("Expected empty q to be kept, got %q", got)
+		}
+		if n, err := c.QueryInt("page"); n != 3 || err != nil {
+			t.Errorf("Expected page=3, got %d (%v)", n, err)
+		}
+		if n, err := c.QueryInt("limit"); n != 0 || err != nil {
+			t.Errorf("Expected missing limit to be 0 without error, got %d (%v)", n, err)
+		}
+		if _, err := c.QueryInt("sort"); err == nil {
+			t.Errorf("Expected error for non-integer sort")
+		}
+		if got := c.QueryArray("tag"); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("Expected tags [a b], got %v", got)
+		}
+		if got := c.QueryArray("missing"); got != nil {
+			t.Errorf("Expected nil for missing array, got %v", got)
+		}
+
+		// The query is parsed once; later changes to the URL are not seen
+		c.Request.URL.RawQuery = "sort=other"
+		if got := c.Query("sort"); got != "price" {
+			t.Errorf("Expected cached sort=price, got %q", got)
+		}
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items?sort=price&q=&page=3&tag=a&tag=b", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+}
+
+// discardWriter is a ResponseWriter that drops the response, so benchmarks
+// measure the framework rather than the recorder
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+// BenchmarkServeHTTP measures a routed request with pooled Contexts against
+// a fresh Context per request, to show what the pool saves
+func BenchmarkServeHTTP(b *testing.B) {
+	engine := New()
+	engine.GET("/users/:id", func(c *Context) {
+		c.Writer.WriteHeader(http.StatusNoContent)
+	})
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := &discardWriter{header: make(http.Header)}
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			engine.ServeHTTP(w, req)
+		}
+	})
+	b.Run("unpooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			engine.ServeHTTP(w, req)
+			// Take back the Context just returned, so the next request
+			// has to allocate its own
+			engine.contextPool.Get()
+		}
+	})
+}
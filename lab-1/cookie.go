@@ -0,0 +1,111 @@
+package goexpress
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// maxCookieSize is the cookie size (name, value and attributes) that all
+// major browsers are guaranteed to store.
+const maxCookieSize = 4096
+
+var (
+	// ErrInvalidSignature is returned by Context.SignedCookie when a
+	// cookie is not in the signed format or its signature does not match.
+	ErrInvalidSignature = errors.New("goexpress: invalid cookie signature")
+
+	// ErrCookieTooLarge is returned by Context.SetSignedCookie when the
+	// signed cookie would exceed the 4096 bytes browsers are required to
+	// store.
+	ErrCookieTooLarge = errors.New("goexpress: signed cookie exceeds 4096 bytes")
+)
+
+// SetSignedCookie signs cookie.Value with HMAC-SHA256 using secret and adds
+// the cookie to the response headers. The value is stored base64-encoded
+// next to its signature, so the usable size is roughly two thirds of the
+// 4096 bytes a browser keeps per cookie; larger cookies are rejected with
+// ErrCookieTooLarge. The signature covers the cookie name, so a value
+// cannot be replayed under a different name. Signing does not encrypt:
+// the value is still readable by the client.
+func (c *Context) SetSignedCookie(cookie *http.Cookie, secret []byte) error {
+	signed := *cookie
+	signed.Value = base64.RawURLEncoding.EncodeToString([]byte(cookie.Value)) +
+		"." + signCookie(cookie.Name, cookie.Value, secret)
+	if len(signed.String()) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+	c.SetCookie(&signed)
+	return nil
+}
+
+// SignedCookie returns the value of the named cookie after verifying its
+// signature with secret. It returns http.ErrNoCookie if the cookie is
+// missing and ErrInvalidSignature if it has been tampered with.
+func (c *Context) SignedCookie(name string, secret []byte) (string, error) {
+	raw, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+
+	encoded, sig, ok := strings.Cut(raw, ".")
+	if !ok {
+		return "", ErrInvalidSignature
+	}
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidSignature
+	}
+	expected := signCookie(name, string(value), secret)
+	if !hmac.Equal([]byte(sig), []byte(expected)) {
+		return "", ErrInvalidSignature
+	}
+	return string(value), nil
+}
+
+// signCookie returns the base64-encoded HMAC-SHA256 of name and value.
+func signCookie(name, value string, secret []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(name))
+	mac.Write([]byte{'='})
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Cookie returns the value of the named request cookie, or
+// http.ErrNoCookie if the request has none.
+func (c *Context) Cookie(name string) (string, error) {
+	cookie, err := c.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+// SetCookie adds cookie to the response headers as given. net/http drops
+// a cookie with an invalid name and logs it.
+func (c *Context) SetCookie(cookie *http.Cookie) {
+	http.SetCookie(c.Writer, cookie)
+}
+
+// SetCookieValue adds a cookie with the given attributes to the response
+// headers. The cookie is HttpOnly, so scripts cannot read it, and
+// SameSite=Lax, so it is not sent with cross-site subrequests or POSTs;
+// use SetCookie for other flags. maxAge is in seconds: zero leaves it a
+// session cookie and a negative value deletes the cookie. secure should be
+// true whenever the site is served over HTTPS.
+func (c *Context) SetCookieValue(name, value string, maxAge int, path, domain string, secure bool) {
+	c.SetCookie(&http.Cookie{
+		Name:     name,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     path,
+		Domain:   domain,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
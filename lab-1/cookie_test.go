@@ -0,0 +1,121 @@
+package goexpress
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestSignedCookie verifies that a signed cookie round-trips and that
+// tampered, renamed or missing cookies are rejected
+func TestSignedCookie(t *testing.T) {
+	secret := []byte("top-secret")
+	signed := func(r *http.Request, name string, secret []byte) (string, error) {
+		return newContext(nil, httptest.NewRecorder(), r, nil).SignedCookie(name, secret)
+	}
+
+	rr := httptest.NewRecorder()
+	c := newContext(nil, rr, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	if err := c.SetSignedCookie(&http.Cookie{Name: "session", Value: "user=42; admin"}, secret); err != nil {
+		t.Fatalf("SetSignedCookie failed: %v", err)
+	}
+	cookie := rr.Result().Cookies()[0]
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookie)
+	value, err := signed(req, "session", secret)
+	if err != nil {
+		t.Fatalf("SignedCookie failed: %v", err)
+	}
+	if value != "user=42; admin" {
+		t.Errorf("Expected value %q, got %q", "user=42; admin", value)
+	}
+
+	if _, err := signed(req, "session", []byte("wrong")); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature with wrong secret, got %v", err)
+	}
+
+	tampered := httptest.NewRequest(http.MethodGet, "/", nil)
+	tampered.AddCookie(&http.Cookie{Name: "session", Value: "dXNlcj0x." + strings.SplitN(cookie.Value, ".", 2)[1]})
+	if _, err := signed(tampered, "session", secret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for tampered value, got %v", err)
+	}
+
+	renamed := httptest.NewRequest(http.MethodGet, "/", nil)
+	renamed.AddCookie(&http.Cookie{Name: "other", Value: cookie.Value})
+	if _, err := signed(renamed, "other", secret); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for renamed cookie, got %v", err)
+	}
+
+	if _, err := signed(httptest.NewRequest(http.MethodGet, "/", nil), "session", secret); !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("Expected http.ErrNoCookie, got %v", err)
+	}
+}
+
+// TestSignedCookieTooLarge verifies that oversized signed cookies are rejected
+func TestSignedCookieTooLarge(t *testing.T) {
+	rr := httptest.NewRecorder()
+	c := newContext(nil, rr, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	err := c.SetSignedCookie(&http.Cookie{Name: "big", Value: strings.Repeat("x", 3500)}, []byte("k"))
+	if !errors.Is(err, ErrCookieTooLarge) {
+		t.Errorf("Expected ErrCookieTooLarge, got %v", err)
+	}
+	if len(rr.Result().Cookies()) != 0 {
+		t.Error("Expected no cookie to be set")
+	}
+}
+
+// TestContextCookies verifies that SetCookieValue sets secure defaults and
+// that Cookie reads the value back or reports a missing cookie
+func TestContextCookies(t *testing.T) {
+	engine := New()
+	engine.POST("/login", func(c *Context) {
+		c.SetCookieValue("session", "abc123", 3600, "/", "example.com", true)
+		c.SetCookie(&http.Cookie{Name: "theme", Value: "dark"})
+		c.Writer.WriteHeader(http.StatusOK)
+	})
+	engine.GET("/me", func(c *Context) {
+		session, err := c.Cookie("session")
+		if _, missing := c.Cookie("missing"); !errors.Is(missing, http.ErrNoCookie) {
+			t.Errorf("Expected http.ErrNoCookie, got %v", missing)
+		}
+		if err != nil {
+			c.String(http.StatusUnauthorized, "no session")
+			return
+		}
+		c.String(http.StatusOK, "%s", session)
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/login", nil))
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 2 {
+		t.Fatalf("Expected 2 cookies, got %d", len(cookies))
+	}
+	session := cookies[0]
+	if session.Value != "abc123" || session.MaxAge != 3600 || session.Path != "/" || session.Domain != "example.com" {
+		t.Errorf("Expected session cookie attributes to be set, got %q", session.String())
+	}
+	if !session.Secure || !session.HttpOnly || session.SameSite != http.SameSiteLaxMode {
+		t.Errorf("Expected Secure, HttpOnly and SameSite=Lax, got %q", session.String())
+	}
+	if cookies[1].HttpOnly {
+		t.Errorf("Expected SetCookie to leave the cookie as given, got %q", cookies[1].String())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(session)
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK || rr.Body.String() != "abc123" {
+		t.Errorf("Expected 200 with the session value, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/me", nil))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a cookie, got %d", rr.Code)
+	}
+}
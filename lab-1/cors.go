@@ -0,0 +1,129 @@
+package goexpress
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions configures the CORS middleware.
+type CORSOptions struct {
+	// AllowOrigins lists the origins allowed to make cross-origin
+	// requests, such as "https://app.example.com". "*" allows any origin
+	AllowOrigins []string
+
+	// AllowOriginFunc, if set, is consulted for origins not listed in
+	// AllowOrigins and allows the origin when it returns true
+	AllowOriginFunc func(origin string) bool
+
+	// AllowMethods lists the methods allowed in preflight responses.
+	// Defaults to GET, HEAD, POST, PUT, PATCH and DELETE
+	AllowMethods []string
+
+	// AllowHeaders lists the request headers allowed in preflight
+	// responses. If empty, the headers the preflight asks for are allowed
+	AllowHeaders []string
+
+	// ExposeHeaders lists the response headers browsers may expose to
+	// scripts beyond the CORS-safelisted ones
+	ExposeHeaders []string
+
+	// AllowCredentials lets browsers send cookies and HTTP authentication
+	// with cross-origin requests. The allowed origin is then always echoed
+	// back, since browsers reject "*" for credentialed requests
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight response. Zero
+	// leaves it to the browser default
+	MaxAge time.Duration
+}
+
+// CORS returns a middleware that implements Cross-Origin Resource Sharing
+// for requests from allowed origins. Preflight requests, OPTIONS requests
+// carrying Access-Control-Request-Method, are answered with 204 No Content
+// and never reach the route handler; for disallowed origins the answer
+// carries no CORS headers, so the browser blocks the actual request.
+// Requests without an Origin header pass through untouched. Register it
+// with Engine.Use rather than on a group, as preflights usually target
+// paths that have no OPTIONS route and global middleware is the only
+// middleware that runs for them.
+func CORS(opts CORSOptions) Middleware {
+	allowMethods := opts.AllowMethods
+	if len(allowMethods) == 0 {
+		allowMethods = []string{
+			http.MethodGet, http.MethodHead, http.MethodPost,
+			http.MethodPut, http.MethodPatch, http.MethodDelete,
+		}
+	}
+	methods := strings.Join(allowMethods, ", ")
+	headers := strings.Join(opts.AllowHeaders, ", ")
+	expose := strings.Join(opts.ExposeHeaders, ", ")
+	anyOrigin := slices.Contains(opts.AllowOrigins, "*")
+	maxAge := ""
+	if opts.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(opts.MaxAge / time.Second))
+	}
+
+	allowed := func(origin string) bool {
+		return anyOrigin || slices.Contains(opts.AllowOrigins, origin) ||
+			opts.AllowOriginFunc != nil && opts.AllowOriginFunc(origin)
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			origin := c.Request.Header.Get("Origin")
+			if origin == "" {
+				next(c)
+				return
+			}
+
+			h := c.Writer.Header()
+			h.Add("Vary", "Origin")
+			preflight := c.Request.Method == http.MethodOptions &&
+				c.Request.Header.Get("Access-Control-Request-Method") != ""
+			if preflight {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+			}
+
+			if !allowed(origin) {
+				if preflight {
+					c.NoContent()
+					return
+				}
+				next(c)
+				return
+			}
+
+			if anyOrigin && !opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if expose != "" {
+					h.Set("Access-Control-Expose-Headers", expose)
+				}
+				next(c)
+				return
+			}
+
+			h.Set("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				h.Set("Access-Control-Allow-Headers", headers)
+			} else if requested := c.Request.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				h.Set("Access-Control-Allow-Headers", requested)
+			}
+			if maxAge != "" {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.NoContent()
+		}
+	}
+}
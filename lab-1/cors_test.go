@@ -0,0 +1,123 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCORS verifies origin matching, simple request headers and preflight
+// short-circuiting
+func TestCORS(t *testing.T) {
+	reached := 0
+	engine := New()
+	engine.Use(CORS(CORSOptions{
+		AllowOrigins: []string{"https://app.example.com"},
+		AllowOriginFunc: func(origin string) bool {
+			return strings.HasSuffix(origin, ".preview.example.com")
+		},
+		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		ExposeHeaders:    []string{"X-Request-Id"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}))
+	engine.GET("/items", func(c *Context) {
+		reached++
+		c.String(http.StatusOK, "items")
+	})
+	engine.POST("/items", func(c *Context) {
+		reached++
+	})
+
+	tests := []struct {
+		name    string
+		method  string
+		origin  string
+		reqMeth string
+		status  int
+		headers map[string]string
+		reached int
+	}{
+		{"no origin", http.MethodGet, "", "", http.StatusOK, map[string]string{
+			"Access-Control-Allow-Origin": "", "Vary": "",
+		}, 1},
+		{"allowed simple", http.MethodGet, "https://app.example.com", "", http.StatusOK, map[string]string{
+			"Access-Control-Allow-Origin":      "https://app.example.com",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Expose-Headers":    "X-Request-Id",
+			"Access-Control-Allow-Methods":     "",
+		}, 1},
+		{"predicate simple", http.MethodGet, "https://pr-7.preview.example.com", "", http.StatusOK, map[string]string{
+			"Access-Control-Allow-Origin": "https://pr-7.preview.example.com",
+		}, 1},
+		{"disallowed simple", http.MethodGet, "https://evil.example", "", http.StatusOK, map[string]string{
+			"Access-Control-Allow-Origin": "", "Vary": "Origin",
+		}, 1},
+		{"allowed preflight", http.MethodOptions, "https://app.example.com", http.MethodPost, http.StatusNoContent, map[string]string{
+			"Access-Control-Allow-Origin":  "https://app.example.com",
+			"Access-Control-Allow-Methods": "GET, HEAD, POST, PUT, PATCH, DELETE",
+			"Access-Control-Allow-Headers": "Content-Type, Authorization",
+			"Access-Control-Max-Age":       "600",
+		}, 0},
+		{"disallowed preflight", http.MethodOptions, "https://evil.example", http.MethodPost, http.StatusNoContent, map[string]string{
+			"Access-Control-Allow-Origin":  "",
+			"Access-Control-Allow-Methods": "",
+		}, 0},
+	}
+	for _, tt := range tests {
+		reached = 0
+		req := httptest.NewRequest(tt.method, "/items", nil)
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.reqMeth != "" {
+			req.Header.Set("Access-Control-Request-Method", tt.reqMeth)
+		}
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, rr.Code)
+		}
+		for name, want := range tt.headers {
+			if got := rr.Header().Get(name); got != want {
+				t.Errorf("%s: expected %s %q, got %q", tt.name, name, want, got)
+			}
+		}
+		if reached != tt.reached {
+			t.Errorf("%s: expected handler to run %d times, got %d", tt.name, tt.reached, reached)
+		}
+	}
+}
+
+// TestCORSWildcard verifies that a wildcard origin is sent as "*" and that
+// preflights echo the requested headers when none are configured
+func TestCORSWildcard(t *testing.T) {
+	engine := New()
+	engine.Use(CORS(CORSOptions{AllowOrigins: []string{"*"}, AllowMethods: []string{"GET"}}))
+	engine.GET("/items", echoHandler)
+
+	req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+	req.Header.Set("Origin", "https://anywhere.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	req.Header.Set("Access-Control-Request-Headers", "x-custom")
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rr.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "*",
+		"Access-Control-Allow-Methods":     "GET",
+		"Access-Control-Allow-Headers":     "x-custom",
+		"Access-Control-Allow-Credentials": "",
+		"Access-Control-Max-Age":           "",
+	}
+	for name, v := range want {
+		if got := rr.Header().Get(name); got != v {
+			t.Errorf("Expected %s %q, got %q", name, v, got)
+		}
+	}
+}
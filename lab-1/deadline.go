@@ -0,0 +1,56 @@
+package goexpress
+
+import (
+	"context"
+	"errors"
+)
+
+// DeadlineReached reports whether the deadline of the request context, such
+// as the one set by Timeout, has passed. Fan-out handlers check it to stop
+// waiting for slow backends and respond with the results gathered so far:
+//
+//	c.HandleDeadline()
+//	results := make(map[string]interface{})
+//	for len(results) < len(backends) && !c.DeadlineReached() {
+//		select {
+//		case r := <-replies:
+//			results[r.Backend] = r.Data
+//		case <-c.Request.Context().Done():
+//		}
+//	}
+//	if len(results) < len(backends) {
+//		c.PartialJSON(http.StatusOK, results)
+//		return
+//	}
+//	c.JSON(http.StatusOK, results)
+func (c *Context) DeadlineReached() bool {
+	return errors.Is(c.Request.Context().Err(), context.DeadlineExceeded)
+}
+
+// HandleDeadline tells the Timeout middleware that the handler responds to
+// the deadline itself, so Timeout waits for it to return and sends its
+// response instead of a 503. The handler must then return promptly once
+// DeadlineReached is true. Call it at the start of the handler; if the
+// deadline has already passed, or the route has no Timeout, it has no
+// effect.
+func (c *Context) HandleDeadline() {
+	tw, ok := c.writer.ResponseWriter.(*timeoutWriter)
+	if !ok {
+		return
+	}
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.timedOut {
+		tw.handlesDeadline = true
+	}
+}
+
+// PartialJSON writes v like JSON and marks the response as incomplete with
+// an X-Partial-Response: true header, by convention for results cut short
+// by the deadline. The status is left to the caller, usually 200: 206
+// Partial Content is reserved for range requests, and caches handle it
+// accordingly.
+func (c *Context) PartialJSON(status int, v interface{}) error {
+	c.Writer.Header().Set("X-Partial-Response", "true")
+	return c.JSON(status, v)
+}
@@ -0,0 +1,149 @@
+package goexpress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is an error carrying the HTTP status to respond with. Pass it
+// to Context.Error to have it rendered by the engine's ErrorRenderer.
+type HTTPError struct {
+	// Status is the HTTP status code, 500 if zero
+	Status int
+
+	// Message describes the error to the client, the status text if empty
+	Message string
+
+	// Err is the underlying cause, for logging only; it is never sent to
+	// the client
+	Err error
+}
+
+// NewHTTPError returns an HTTPError with the given status and message.
+func NewHTTPError(status int, message string) *HTTPError {
+	return &HTTPError{Status: status, Message: message}
+}
+
+func (e *HTTPError) Error() string {
+	msg := fmt.Sprintf("%d %s", e.status(), e.message())
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying cause.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+func (e *HTTPError) status() int {
+	if e.Status == 0 {
+		return http.StatusInternalServerError
+	}
+	return e.Status
+}
+
+func (e *HTTPError) message() string {
+	if e.Message == "" {
+		return http.StatusText(e.status())
+	}
+	return e.Message
+}
+
+// httpError returns err as an HTTPError. Errors that are not an HTTPError
+// become a 500 whose message does not reveal err.
+func httpError(err error) *HTTPError {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		return he
+	}
+	return &HTTPError{Status: http.StatusInternalServerError, Err: err}
+}
+
+// ErrorRenderer writes the response for an error passed to Context.Error.
+type ErrorRenderer func(c *Context, err error)
+
+// SetErrorRenderer sets how the engine renders errors passed to
+// Context.Error, including its own 404 Not Found and 405 Method Not
+// Allowed responses. The default is TextError; select ProblemJSON for
+// RFC 7807 problem documents.
+func (e *Engine) SetErrorRenderer(renderer ErrorRenderer) {
+	e.errorRenderer = renderer
+}
+
+// Error renders err with the engine's ErrorRenderer. An HTTPError sets the
+// status and client message; any other error is reported as 500 Internal
+// Server Error without exposing its text. Browsers get the HTML page set
+// for the status with SetStatusTemplate instead, if any. If the response
+// has already been started, Error does nothing.
+func (c *Context) Error(err error) {
+	if c.writer.written || c.renderStatusPage(err) {
+		return
+	}
+	renderer := TextError
+	if c.engine != nil && c.engine.errorRenderer != nil {
+		renderer = c.engine.errorRenderer
+	}
+	renderer(c, err)
+}
+
+// TextError is the default ErrorRenderer. It writes the error message as
+// plain text, like http.Error.
+func TextError(c *Context, err error) {
+	he := httpError(err)
+	http.Error(c.Writer, he.message(), he.status())
+}
+
+// Problem is an RFC 7807 problem document. Handlers may pass a *Problem to
+// Context.Error to control every field when ProblemJSON is selected.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
+
+// ProblemJSON is an ErrorRenderer that writes errors as RFC 7807
+// application/problem+json documents. A *Problem is written as given,
+// with missing fields filled in. An HTTPError maps to type "about:blank",
+// its status with the standard status text as title, its message as
+// detail, and the request path as instance. Other errors become a 500
+// problem with no detail.
+func ProblemJSON(c *Context, err error) {
+	var p Problem
+	var custom *Problem
+	if errors.As(err, &custom) {
+		p = *custom
+	} else {
+		he := httpError(err)
+		p = Problem{Status: he.status(), Detail: he.Message}
+	}
+
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if p.Instance == "" {
+		p.Instance = c.Request.URL.Path
+	}
+
+	// Problem holds only strings and an int, so encoding cannot fail
+	body, _ := json.Marshal(p)
+	c.render(p.Status, "application/problem+json", body)
+}
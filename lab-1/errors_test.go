@@ -0,0 +1,98 @@
+package goexpress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestContextError verifies that errors are rendered as plain text by default
+func TestContextError(t *testing.T) {
+	engine := New()
+	engine.GET("/conflict", func(c *Context) {
+		c.Error(NewHTTPError(http.StatusConflict, "user already exists"))
+	})
+	engine.GET("/wrapped", func(c *Context) {
+		c.Error(fmt.Errorf("loading user: %w", &HTTPError{Status: http.StatusForbidden}))
+	})
+	engine.GET("/internal", func(c *Context) {
+		c.Error(errors.New("database password rejected"))
+	})
+	engine.GET("/late", func(c *Context) {
+		c.String(http.StatusOK, "partial")
+		c.Error(NewHTTPError(http.StatusBadRequest, "too late"))
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/conflict", http.StatusConflict, "user already exists\n"},
+		{"/wrapped", http.StatusForbidden, "Forbidden\n"},
+		{"/internal", http.StatusInternalServerError, "Internal Server Error\n"},
+		{"/late", http.StatusOK, "partial"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, rr.Code)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.body, rr.Body.String())
+		}
+	}
+}
+
+// TestProblemJSON verifies that the ProblemJSON renderer writes RFC 7807
+// documents for handler errors and the engine's own 404 and 405 responses
+func TestProblemJSON(t *testing.T) {
+	engine := New()
+	engine.SetErrorRenderer(ProblemJSON)
+	engine.GET("/users/:id", func(c *Context) {
+		c.Error(NewHTTPError(http.StatusNotFound, "no user with id "+c.Param("id")))
+	})
+	engine.GET("/quota", func(c *Context) {
+		c.Error(&Problem{
+			Type:   "https://example.com/probs/out-of-credit",
+			Title:  "You do not have enough credit.",
+			Status: http.StatusForbidden,
+		})
+	})
+	engine.GET("/internal", func(c *Context) {
+		c.Error(errors.New("database password rejected"))
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		want   Problem
+	}{
+		{http.MethodGet, "/users/7", Problem{"about:blank", "Not Found", 404, "no user with id 7", "/users/7"}},
+		{http.MethodGet, "/quota", Problem{"https://example.com/probs/out-of-credit", "You do not have enough credit.", 403, "", "/quota"}},
+		{http.MethodGet, "/internal", Problem{"about:blank", "Internal Server Error", 500, "", "/internal"}},
+		{http.MethodGet, "/missing", Problem{"about:blank", "Not Found", 404, "404 page not found", "/missing"}},
+		{http.MethodPost, "/quota", Problem{"about:blank", "Method Not Allowed", 405, "", "/quota"}},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+		if rr.Code != tt.want.Status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.want.Status, rr.Code)
+		}
+		if got := rr.Header().Get("Content-Type"); got != "application/problem+json" {
+			t.Errorf("%s %s: expected problem+json, got %q", tt.method, tt.path, got)
+		}
+		var got Problem
+		if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+			t.Fatalf("%s %s: invalid JSON %q: %v", tt.method, tt.path, rr.Body.String(), err)
+		}
+		if got != tt.want {
+			t.Errorf("%s %s: expected %+v, got %+v", tt.method, tt.path, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package goexpress
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// checkFraming rejects requests whose body framing is ambiguous, the root
+// of HTTP request smuggling: a front-end proxy and this server could
+// disagree on where the request ends, letting an attacker hide a second
+// request in the body of the first. Rejected are requests that carry both
+// Content-Length and Transfer-Encoding, a Transfer-Encoding other than a
+// single "chunked", and several or non-numeric Content-Length values.
+// net/http already refuses most of these while parsing; this check also
+// covers requests that reach the engine from other servers or handler
+// chains, and logs every rejection for visibility. A rejected request gets
+// 400 Bad Request and its connection is closed, since the rest of the byte
+// stream cannot be trusted. It reports whether the request may continue.
+func (e *Engine) checkFraming(w http.ResponseWriter, r *http.Request) bool {
+	reason := framingError(r)
+	if reason == "" {
+		return true
+	}
+	log.Printf("[Framing] rejected %s %s from %s: %s\n", r.Method, r.URL.Path, r.RemoteAddr, reason)
+	w.Header().Set("Connection", "close")
+	http.Error(w, "ambiguous request framing", http.StatusBadRequest)
+	return false
+}
+
+// framingError describes what makes the framing of r ambiguous, or returns
+// "" if it is sound.
+func framingError(r *http.Request) string {
+	te := r.TransferEncoding
+	if len(te) == 0 {
+		for _, value := range r.Header.Values("Transfer-Encoding") {
+			for _, coding := range strings.Split(value, ",") {
+				te = append(te, strings.TrimSpace(coding))
+			}
+		}
+	}
+	contentLength := r.Header.Values("Content-Length")
+
+	if len(te) > 0 {
+		if len(contentLength) > 0 {
+			return "both Content-Length and Transfer-Encoding"
+		}
+		if len(te) != 1 || !strings.EqualFold(te[0], "chunked") {
+			return "unsupported Transfer-Encoding " + strings.Join(te, ", ")
+		}
+	}
+	if len(contentLength) > 1 {
+		return "multiple Content-Length values"
+	}
+	if len(contentLength) == 1 && !isDigits(strings.TrimSpace(contentLength[0])) {
+		return "invalid Content-Length " + contentLength[0]
+	}
+	return ""
+}
@@ -2,9 +2,19 @@ package goexpress
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"html/template"
 	"log"
+	"net"
 	"net/http"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
 // Engine is the core type of the web framework,
@@ -12,6 +22,53 @@ import (
 type Engine struct {
 	config *Config
 	server *http.Server
+
+	// closing is closed when Shutdown begins, see ServerClosing.
+	closing   chan struct{}
+	closeOnce sync.Once
+
+	// mounts holds sub-engines ordered by descending prefix length.
+	mounts []mount
+
+	// inflight and conns count active requests and open connections
+	// for the shutdown progress log.
+	inflight atomic.Int64
+	conns    atomic.Int64
+
+	// trees holds the routing tree of each method.
+	trees     map[string]*node
+	maxParams int
+
+	// methodNotAllowed handles requests whose path is registered only for
+	// other methods, see SetMethodNotAllowed.
+	methodNotAllowed HandlerFunc
+
+	// errorRenderer renders errors passed to Context.Error, see
+	// SetErrorRenderer.
+	errorRenderer ErrorRenderer
+
+	// htmlTemplates and statusTemplates render HTML error pages, see
+	// SetStatusTemplate.
+	htmlTemplates   *template.Template
+	statusTemplates map[int]string
+
+	// shutdownHooks run after the server drains, see OnShutdown.
+	shutdownHooks []func(ctx context.Context) error
+
+	// contextPool recycles the Context of finished requests.
+	contextPool sync.Pool
+
+	// defaultHeaders is set on every routed response, see DefaultHeaders.
+	defaultHeaders http.Header
+
+	// capture records incoming requests, see EnableCapture.
+	capture *capture
+
+	// middleware is the global chain added with Use.
+	middleware []Middleware
+
+	// bodyTransforms is applied to request bodies before dispatch.
+	bodyTransforms []BodyTransform
 }
 
 // New returns a new Engine instance using the default configuration.
@@ -23,14 +80,24 @@ func New() *Engine {
 // The Engine implements http.Handler: the ServeHTTP method is invoked for each request.
 func NewWithConfig(config *Config) *Engine {
 	engine := &Engine{
-		config: config,
+		config:           config,
+		closing:          make(chan struct{}),
+		trees:            make(map[string]*node),
+		methodNotAllowed: methodNotAllowed,
+		errorRenderer:    TextError,
 	}
 
 	engine.server = &http.Server{
-		Addr:         config.Port,
-		Handler:      engine,
-		ReadTimeout:  config.ReadTimeout,
-		WriteTimeout: config.WriteTimeout,
+		Addr:              config.Port,
+		Handler:           engine,
+		ReadTimeout:       config.ReadTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		IdleTimeout:       config.IdleTimeout,
+		MaxHeaderBytes:    config.MaxHeaderBytes,
+		TLSConfig:         config.TLSConfig,
+		ConnContext:       connContext,
+		ConnState:         engine.trackConn,
 	}
 
 	return engine
@@ -39,10 +106,106 @@ func NewWithConfig(config *Config) *Engine {
 // ServeHTTP implements the http.Handler interface for Engine.
 // It is invoked by the net/http package for every HTTP request.
 func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "text/plain")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprintf(w, "Hello from GoExpress!\n")
-	fmt.Fprintf(w, "You requested: %s %s\n", r.Method, r.URL.Path)
+	e.inflight.Add(1)
+	defer e.inflight.Add(-1)
+
+	if !e.checkFraming(w, r) {
+		return
+	}
+	if e.capture != nil {
+		e.capture.record(r)
+	}
+
+	if max := e.config.MaxURILength; max > 0 && len(r.URL.RequestURI()) > max {
+		http.Error(w, http.StatusText(http.StatusRequestURITooLong), http.StatusRequestURITooLong)
+		return
+	}
+	if !e.checkHost(w, r) {
+		return
+	}
+	release, ok := e.acquireConnSlot(w, r)
+	if !ok {
+		return
+	}
+	defer release()
+
+	e.handle(w, r)
+}
+
+// handle dispatches a request that has passed the server-level checks
+// in ServeHTTP.
+func (e *Engine) handle(w http.ResponseWriter, r *http.Request) {
+	h := w.Header()
+	for name, values := range e.defaultHeaders {
+		h[name] = slices.Clone(values)
+	}
+	if !e.applyBodyTransforms(w, r) {
+		return
+	}
+
+	handler := HandlerFunc(notFound)
+	var ps *params
+	var contentType string
+	if sub, req, ok := e.matchMount(r); ok {
+		// The sub-engine runs its own chain inside this engine's, and
+		// sees whatever the outer middleware put on the request context
+		handler = func(c *Context) {
+			sub.handle(c.Writer, req.WithContext(c.Request.Context()))
+		}
+	} else if rt, matched, ok := e.match(r); ok {
+		handler, ps = rt.group.wrap(rt.handler), matched
+		contentType = rt.resolvedContentType()
+	} else if e.methodNotAllowed != nil {
+		if allow := e.allowedMethods(r); len(allow) > 0 {
+			w.Header().Set("Allow", strings.Join(allow, ", "))
+			handler = e.methodNotAllowed
+		}
+	}
+	c, _ := e.contextPool.Get().(*Context)
+	if c == nil {
+		c = &Context{}
+	}
+	c.reset(e, w, r, ps)
+	c.writer.contentType = contentType
+	chain(handler, e.middleware)(c)
+	// A panicking chain skips this, so a Context is never reused while
+	// net/http may still be handling the panic
+	e.contextPool.Put(c)
+}
+
+// notFound is the handler for requests that match no route.
+func notFound(c *Context) {
+	c.Error(NewHTTPError(http.StatusNotFound, "404 page not found"))
+}
+
+// methodNotAllowed is the default handler for requests whose path is
+// only registered for other methods.
+func methodNotAllowed(c *Context) {
+	c.Error(NewHTTPError(http.StatusMethodNotAllowed, ""))
+}
+
+// SetMethodNotAllowed sets the handler for requests whose path matches a
+// route registered for other methods only. The Allow header listing those
+// methods is already set when handler runs, and the global middleware
+// wraps it like any route. By default such requests get a plain 405
+// response; passing nil disables the check so they fall through to the
+// 404 response instead.
+func (e *Engine) SetMethodNotAllowed(handler HandlerFunc) {
+	e.methodNotAllowed = handler
+}
+
+// DefaultHeaders sets headers to add to every response the engine routes,
+// including 404 and 405 responses and those of mounted engines, such as
+// X-App-Version. They are set before the middleware and handler run, so
+// both can override or delete them. Requests rejected before routing, for
+// example by AllowedHosts, do not get them. Each call replaces the headers
+// of the previous one; it must not be called while the engine is serving
+// requests.
+func (e *Engine) DefaultHeaders(headers map[string]string) {
+	e.defaultHeaders = make(http.Header, len(headers))
+	for name, value := range headers {
+		e.defaultHeaders.Set(name, value)
+	}
 }
 
 // Run starts the HTTP server and begins serving requests.
@@ -50,21 +213,178 @@ func (e *Engine) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // or encounters an error.
 func (e *Engine) Run() error {
 	log.Printf("GoExpress server starting on http://localhost%s\n", e.config.Port)
-	err := e.server.ListenAndServe()
+	return e.serve(func(ln net.Listener) error {
+		return e.server.Serve(ln)
+	})
+}
+
+// RunTLS is like Run but serves HTTPS using the certificate and matching
+// private key in the given PEM files. If Config.TLSConfig already provides
+// certificates, both file names may be empty. Shutdown stops it the same
+// way as Run.
+func (e *Engine) RunTLS(certFile, keyFile string) error {
+	log.Printf("GoExpress server starting on https://localhost%s\n", e.config.Port)
+	return e.serve(func(ln net.Listener) error {
+		return e.server.ServeTLS(ln, certFile, keyFile)
+	})
+}
+
+// RunUntilSignal runs the server like Run until the process receives
+// SIGINT or SIGTERM, then shuts it down with Shutdown, giving it
+// shutdownTimeout to drain requests and run the OnShutdown hooks. It
+// returns the error of the server, such as failing to listen, or of the
+// shutdown. Use Run and Shutdown directly for other stop conditions.
+func (e *Engine) RunUntilSignal(shutdownTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	return e.runUntil(ctx, shutdownTimeout)
+}
+
+// runUntil runs the server until ctx is done, then shuts it down within
+// shutdownTimeout.
+func (e *Engine) runUntil(ctx context.Context, shutdownTimeout time.Duration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- e.Run()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+	log.Println("Shutdown signal received")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	err := e.Shutdown(shutdownCtx)
+	return errors.Join(<-serveErr, err)
+}
+
+// serve opens the listener and passes it to serveFn, treating a graceful
+// shutdown as success.
+func (e *Engine) serve(serveFn func(ln net.Listener) error) error {
+	ln, err := e.listen()
+	if err != nil {
+		return fmt.Errorf("listen error: %w", err)
+	}
+	if e.config.ConnFilter != nil {
+		ln = &filterListener{Listener: ln, filter: e.config.ConnFilter}
+	}
+	err = serveFn(ln)
 	if err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("server error: %w", err)
 	}
 	return nil
 }
 
+// ServerClosing returns a channel that is closed as soon as Shutdown begins.
+// Long-lived handlers such as SSE streams should select on it and return,
+// otherwise Shutdown waits for them until its context expires.
+func (e *Engine) ServerClosing() <-chan struct{} {
+	return e.closing
+}
+
+// signalClosing closes the ServerClosing channel of e and of every
+// engine mounted on it.
+func (e *Engine) signalClosing() {
+	e.closeOnce.Do(func() { close(e.closing) })
+	for _, m := range e.mounts {
+		m.engine.signalClosing()
+	}
+}
+
+// listen creates the TCP listener for the server, applying the socket
+// options selected in the configuration.
+func (e *Engine) listen() (net.Listener, error) {
+	addr := e.server.Addr
+	if addr == "" {
+		addr = ":http"
+	}
+
+	var lc net.ListenConfig
+	if e.config.ListenConfig != nil {
+		lc = *e.config.ListenConfig
+	}
+	if e.config.ReusePort {
+		control := lc.Control
+		lc.Control = func(network, address string, c syscall.RawConn) error {
+			if control != nil {
+				if err := control(network, address, c); err != nil {
+					return err
+				}
+			}
+			return reusePortControl(network, address, c)
+		}
+	}
+	return lc.Listen(context.Background(), "tcp", addr)
+}
+
 // Shutdown gracefully stops the HTTP server with the given context.
-// It waits for active requests to finish before shutting down.
+// It waits for active requests to finish before shutting down, then runs
+// the hooks registered with OnShutdown. With Config.PreStopDelay, the
+// server first keeps serving new requests for that long; the delay counts
+// against the deadline of ctx. If draining or any hook fails, the errors
+// are joined and returned once every hook has run.
 func (e *Engine) Shutdown(ctx context.Context) error {
+	if d := e.config.PreStopDelay; d > 0 {
+		log.Printf("Waiting %v before shutting down...\n", d)
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
 	log.Println("Shutting down server gracefully...")
-	err := e.server.Shutdown(ctx)
-	if err != nil {
-		return fmt.Errorf("shutdown error: %w", err)
+	e.signalClosing()
+	if interval := e.config.ShutdownLogInterval; interval > 0 {
+		stop := make(chan struct{})
+		defer close(stop)
+		go e.logDrainProgress(interval, stop)
+	}
+
+	var errs []error
+	if err := e.server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("shutdown error: %w", err))
+	}
+	for i := len(e.shutdownHooks) - 1; i >= 0; i-- {
+		if err := e.shutdownHooks[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook error: %w", err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
 	log.Println("Server stopped successfully")
 	return nil
 }
+
+// OnShutdown registers hook to run during Shutdown once in-flight requests
+// have drained, to release resources such as database pools or to flush
+// buffered logs. Hooks run in reverse registration order, so resources
+// can be released in the opposite order to their setup, and receive the
+// context passed to Shutdown, sharing its deadline. They also run when
+// draining times out, in which case that context is already done. A
+// failing hook does not stop the others. OnShutdown must not be called
+// while Shutdown is running.
+func (e *Engine) OnShutdown(hook func(ctx context.Context) error) {
+	e.shutdownHooks = append(e.shutdownHooks, hook)
+}
+
+// logDrainProgress logs the remaining in-flight requests and open
+// connections every interval until stop is closed.
+func (e *Engine) logDrainProgress(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			log.Printf("Waiting for %d in-flight requests on %d open connections...\n",
+				e.inflight.Load(), e.conns.Load())
+		}
+	}
+}
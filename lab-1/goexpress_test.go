@@ -1,12 +1,29 @@
 package goexpress
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 )
 
+// echoHandler responds with the method and path of the request
+func echoHandler(c *Context) {
+	fmt.Fprintf(c.Writer, "You requested: %s %s\n", c.Request.Method, c.Request.URL.Path)
+}
+
 // TestNew verifies that New() creates an Engine with default configuration
 func TestNew(t *testing.T) {
 	engine := New()
@@ -31,6 +48,15 @@ func TestNew(t *testing.T) {
 	if engine.server == nil {
 		t.Fatal("Engine server is nil, expected http.Server")
 	}
+	if engine.server.ReadHeaderTimeout != 5*time.Second {
+		t.Errorf("Expected ReadHeaderTimeout 5s, got %v", engine.server.ReadHeaderTimeout)
+	}
+	if engine.server.IdleTimeout != 60*time.Second {
+		t.Errorf("Expected IdleTimeout 60s, got %v", engine.server.IdleTimeout)
+	}
+	if engine.server.MaxHeaderBytes != 1<<20 {
+		t.Errorf("Expected MaxHeaderBytes 1MB, got %d", engine.server.MaxHeaderBytes)
+	}
 }
 
 // TestWithConfig verifies that NewWithConfig() creates an Engine with custom configuration,
@@ -61,6 +87,11 @@ func TestWithConfig(t *testing.T) {
 	if engine.server == nil {
 		t.Fatal("Engine server is nil, expected http.Server")
 	}
+	if engine.server.ReadHeaderTimeout != 0 || engine.server.IdleTimeout != 0 {
+		t.Errorf("Expected unset ReadHeaderTimeout and IdleTimeout to stay zero, got %v and %v",
+			engine.server.ReadHeaderTimeout, engine.server.IdleTimeout)
+	}
+	engine.GET("/", echoHandler)
 
 	// Start server in a goroutine
 	done := make(chan struct{})
@@ -157,3 +188,631 @@ func TestGracefulShutdown(t *testing.T) {
 	}
 	t.Log("Graceful shutdown test passed")
 }
+
+// TestServerClosing verifies that a long-lived handler watching ServerClosing
+// returns when Shutdown begins, so shutdown does not wait for the timeout
+func TestServerClosing(t *testing.T) {
+	config := DefaultConfig()
+	config.Port = ":8084"
+	engine := NewWithConfig(config)
+
+	started := make(chan struct{})
+	engine.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		close(started)
+		// Simulate a stream that only ends when the server is closing
+		<-engine.ServerClosing()
+	})
+
+	go engine.Run()
+	time.Sleep(100 * time.Millisecond)
+
+	go func() {
+		resp, err := http.Get("http://localhost:8084/stream")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	begin := time.Now()
+	if err := engine.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Errorf("Expected shutdown to finish promptly, took %v", elapsed)
+	}
+}
+
+// TestContextServerClosing verifies that routed handlers, mounted ones
+// included, see ServerClosing close through their Context on Shutdown
+func TestContextServerClosing(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	started := make(chan struct{}, 2)
+	stream := func(c *Context) {
+		started <- struct{}{}
+		select {
+		case <-c.ServerClosing():
+			c.String(http.StatusOK, "closing")
+		case <-time.After(5 * time.Second):
+			c.String(http.StatusOK, "timed out")
+		}
+	}
+	events := New()
+	events.GET("/", stream)
+	engine := New()
+	engine.GET("/stream", stream)
+	engine.MountEngine("/events", events)
+
+	recorders := []*httptest.ResponseRecorder{httptest.NewRecorder(), httptest.NewRecorder()}
+	done := make(chan struct{})
+	for i, target := range []string{"/stream", "/events"} {
+		go func() {
+			engine.ServeHTTP(recorders[i], httptest.NewRequest(http.MethodGet, target, nil))
+			done <- struct{}{}
+		}()
+	}
+	<-started
+	<-started
+
+	if err := engine.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	<-done
+	<-done
+	for _, rr := range recorders {
+		if got := rr.Body.String(); got != "closing" {
+			t.Errorf("Expected body %q, got %q", "closing", got)
+		}
+	}
+}
+
+// TestMaxURILength verifies that requests with an overly long path or query
+// are rejected with 414 while shorter ones are served normally
+func TestMaxURILength(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxURILength = 32
+	engine := NewWithConfig(config)
+	engine.GET("/short", echoHandler)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/short", http.StatusOK},
+		{"/" + strings.Repeat("a", 40), http.StatusRequestURITooLong},
+		{"/search?q=" + strings.Repeat("b", 40), http.StatusRequestURITooLong},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.target, tt.status, rr.Code)
+		}
+	}
+}
+
+// TestAllowedHosts verifies Host header validation and the AllowedHosts allow-list
+func TestAllowedHosts(t *testing.T) {
+	config := DefaultConfig()
+	config.AllowedHosts = []string{"example.com", "API.example.com"}
+	engine := NewWithConfig(config)
+	var seen string
+	engine.GET("/", func(c *Context) {
+		seen = c.Request.Host
+	})
+
+	tests := []struct {
+		host   string
+		status int
+	}{
+		{"example.com", http.StatusOK},
+		{"EXAMPLE.com:8080", http.StatusOK},
+		{"api.example.com.", http.StatusOK},
+		{"evil.com", http.StatusMisdirectedRequest},
+		{"", http.StatusBadRequest},
+		{"example.com:http", http.StatusBadRequest},
+		{"exa mple.com", http.StatusBadRequest},
+		{"example.com/path", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = tt.host
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("Host %q: expected status %d, got %d", tt.host, tt.status, rr.Code)
+		}
+	}
+
+	// Accepted hosts reach the handler normalized
+	for host, want := range map[string]string{
+		"EXAMPLE.com:8080": "example.com",
+		"api.example.com.": "api.example.com",
+	} {
+		seen = ""
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+		if seen != want {
+			t.Errorf("Host %q: expected handler to see %q, got %q", host, want, seen)
+		}
+	}
+
+	// Without an allow-list, any well-formed host (or none) is served
+	engine = New()
+	engine.GET("/", func(c *Context) {
+		seen = c.Request.Host
+	})
+	for host, want := range map[string]string{"Anything.test": "anything.test", "[::1]:8080": "[::1]", "": ""} {
+		seen = "unset"
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Host = host
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Errorf("Host %q: expected status 200, got %d", host, rr.Code)
+		}
+		if seen != want {
+			t.Errorf("Host %q: expected handler to see %q, got %q", host, want, seen)
+		}
+	}
+}
+
+// TestAmbiguousFraming verifies that requests with ambiguous body framing
+// are rejected with 400 before routing and logged
+func TestAmbiguousFraming(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	engine := New()
+	reached := false
+	engine.POST("/", func(c *Context) {
+		reached = true
+		c.Writer.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name             string
+		transferEncoding []string
+		header           map[string][]string
+		status           int
+	}{
+		{"content-length only", nil, map[string][]string{"Content-Length": {"4"}}, http.StatusOK},
+		{"chunked only", []string{"chunked"}, nil, http.StatusOK},
+		{"chunked header only", nil, map[string][]string{"Transfer-Encoding": {"chunked"}}, http.StatusOK},
+		{"both", []string{"chunked"}, map[string][]string{"Content-Length": {"4"}}, http.StatusBadRequest},
+		{"both headers", nil, map[string][]string{"Transfer-Encoding": {"chunked"}, "Content-Length": {"4"}}, http.StatusBadRequest},
+		{"gzip coding", nil, map[string][]string{"Transfer-Encoding": {"gzip, chunked"}}, http.StatusBadRequest},
+		{"unknown coding", []string{"xchunked"}, nil, http.StatusBadRequest},
+		{"duplicate chunked", nil, map[string][]string{"Transfer-Encoding": {"chunked", "chunked"}}, http.StatusBadRequest},
+		{"multiple lengths", nil, map[string][]string{"Content-Length": {"4", "5"}}, http.StatusBadRequest},
+		{"signed length", nil, map[string][]string{"Content-Length": {"+4"}}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		reached = false
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body"))
+		req.Header.Del("Content-Length")
+		req.TransferEncoding = tt.transferEncoding
+		for name, values := range tt.header {
+			req.Header[name] = values
+		}
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, rr.Code)
+		}
+		rejected := tt.status == http.StatusBadRequest
+		if reached == rejected {
+			t.Errorf("%s: expected handler reached %v, got %v", tt.name, !rejected, reached)
+		}
+		if rejected {
+			if got := rr.Header().Get("Connection"); got != "close" {
+				t.Errorf("%s: expected Connection close, got %q", tt.name, got)
+			}
+			if !strings.Contains(buf.String(), "[Framing] rejected POST /") {
+				t.Errorf("%s: expected rejection to be logged, got %q", tt.name, buf.String())
+			}
+		} else if buf.Len() != 0 {
+			t.Errorf("%s: expected no log output, got %q", tt.name, buf.String())
+		}
+	}
+}
+
+// TestMaxConnRequests verifies that a connection with too many requests
+// in flight is answered with 503
+func TestMaxConnRequests(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxConnRequests = 1
+	engine := NewWithConfig(config)
+	engine.GET("/", echoHandler)
+
+	// Simulate the per-connection context created by the server
+	ctx := engine.server.ConnContext(context.Background(), nil)
+	state := ctx.Value(connStateKey{}).(*connState)
+
+	state.inflight.Store(1)
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 with a busy connection, got %d", rr.Code)
+	}
+
+	state.inflight.Store(0)
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with an idle connection, got %d", rr.Code)
+	}
+	if n := state.inflight.Load(); n != 0 {
+		t.Errorf("Expected in-flight count to be released, got %d", n)
+	}
+}
+
+// TestMountEngine verifies that requests under a mounted prefix are handled
+// by the sub-engine with the prefix stripped
+func TestMountEngine(t *testing.T) {
+	billing := New()
+	billing.GET("/", echoHandler)
+	billing.GET("/invoices", echoHandler)
+	admin := New()
+	admin.GET("/users", echoHandler)
+
+	app := New()
+	app.GET("/billingx", echoHandler)
+	app.MountEngine("/billing/", billing)
+	app.MountEngine("/billing/admin", admin)
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/billing/invoices", "You requested: GET /invoices\n"},
+		{"/billing", "You requested: GET /\n"},
+		{"/billing/admin/users", "You requested: GET /users\n"},
+		{"/billingx", "You requested: GET /billingx\n"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if !strings.HasSuffix(rr.Body.String(), tt.want) {
+			t.Errorf("%s: expected body ending in %q, got %q", tt.target, tt.want, rr.Body.String())
+		}
+	}
+}
+
+// TestMountEngineMiddleware verifies that the parent's global middleware
+// wraps requests delegated to a mounted engine, outside the sub-engine's own
+func TestMountEngineMiddleware(t *testing.T) {
+	var order []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) {
+				order = append(order, name+" in")
+				next(c)
+				order = append(order, name+" out")
+			}
+		}
+	}
+
+	api := New()
+	api.Use(record("api"))
+	api.GET("/status", func(c *Context) {
+		order = append(order, "handler "+c.Request.URL.Path)
+	})
+	app := New()
+	app.Use(record("app"))
+	app.MountEngine("/api", api)
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/status", nil))
+	want := []string{"app in", "api in", "handler /status", "api out", "app out"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+}
+
+// TestDefaultHeaders verifies that default headers are set on routed
+// responses, mounted engines included, and that handlers can override them
+func TestDefaultHeaders(t *testing.T) {
+	api := New()
+	api.DefaultHeaders(map[string]string{"X-Service": "api"})
+	api.GET("/status", echoHandler)
+
+	app := New()
+	app.DefaultHeaders(map[string]string{"x-app-version": "1.2.0", "X-Service": "web"})
+	app.GET("/", echoHandler)
+	app.GET("/legacy", func(c *Context) {
+		c.Writer.Header().Set("X-App-Version", "0.9.0")
+		c.Writer.Header().Add("X-Service", "legacy")
+		c.Writer.WriteHeader(http.StatusOK)
+	})
+	app.MountEngine("/api", api)
+
+	tests := []struct {
+		target  string
+		version string
+		service []string
+	}{
+		{"/", "1.2.0", []string{"web"}},
+		{"/missing", "1.2.0", []string{"web"}},
+		{"/legacy", "0.9.0", []string{"web", "legacy"}},
+		{"/api/status", "1.2.0", []string{"api"}},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		app.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if got := rr.Header().Get("X-App-Version"); got != tt.version {
+			t.Errorf("%s: expected X-App-Version %q, got %q", tt.target, tt.version, got)
+		}
+		if got := rr.Header().Values("X-Service"); !slices.Equal(got, tt.service) {
+			t.Errorf("%s: expected X-Service %v, got %v", tt.target, tt.service, got)
+		}
+	}
+
+	// Adding to a default in one response must not leak into the next
+	rr := httptest.NewRecorder()
+	app.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rr.Header().Values("X-Service"); !slices.Equal(got, []string{"web"}) {
+		t.Errorf("Expected defaults to be unchanged, got X-Service %v", got)
+	}
+}
+
+// TestShutdownDrainLog verifies that the drain progress log reports
+// the remaining in-flight requests and open connections
+func TestShutdownDrainLog(t *testing.T) {
+	engine := New()
+	engine.inflight.Store(2)
+	engine.conns.Store(1)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		engine.logDrainProgress(20*time.Millisecond, stop)
+		close(done)
+	}()
+	time.Sleep(70 * time.Millisecond)
+	close(stop)
+	<-done
+
+	want := "Waiting for 2 in-flight requests on 1 open connections"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("Expected log to contain %q, got %q", want, buf.String())
+	}
+}
+
+// TestListenConfig verifies that Run creates its listener with the configured ListenConfig
+func TestListenConfig(t *testing.T) {
+	controlled := make(chan string, 1)
+	config := DefaultConfig()
+	config.Port = ":8085"
+	config.ListenConfig = &net.ListenConfig{
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			controlled <- address
+			return nil
+		},
+	}
+	engine := NewWithConfig(config)
+	engine.GET("/", echoHandler)
+
+	done := make(chan struct{})
+	go func() {
+		if err := engine.Run(); err != nil {
+			t.Errorf("Server failed: %v", err)
+		}
+		close(done)
+	}()
+
+	select {
+	case addr := <-controlled:
+		if !strings.HasSuffix(addr, ":8085") {
+			t.Errorf("Expected control callback for port 8085, got %s", addr)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected the ListenConfig control callback to run")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := engine.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	<-done
+}
+
+// TestConnFilter verifies that connections rejected by ConnFilter are
+// closed before any request is served
+func TestConnFilter(t *testing.T) {
+	var allow atomic.Bool
+	config := DefaultConfig()
+	config.Port = ":8086"
+	config.ConnFilter = func(c net.Conn) bool {
+		return allow.Load()
+	}
+	engine := NewWithConfig(config)
+	engine.GET("/", echoHandler)
+
+	go engine.Run()
+	time.Sleep(100 * time.Millisecond)
+	defer engine.Shutdown(context.Background())
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	if resp, err := client.Get("http://localhost:8086"); err == nil {
+		resp.Body.Close()
+		t.Errorf("Expected rejected connection to fail, got status %d", resp.StatusCode)
+	}
+
+	allow.Store(true)
+	resp, err := client.Get("http://localhost:8086")
+	if err != nil {
+		t.Fatalf("Expected allowed connection to succeed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+}
+
+// TestRunTLS verifies that RunTLS serves HTTPS with certificates from
+// Config.TLSConfig and stops on Shutdown
+func TestRunTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(nil)
+	cert := ts.TLS.Certificates[0]
+	ts.Close()
+
+	config := DefaultConfig()
+	config.Port = ":8087"
+	config.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	engine := NewWithConfig(config)
+	engine.GET("/", echoHandler)
+
+	done := make(chan error, 1)
+	go func() { done <- engine.RunTLS("", "") }()
+	time.Sleep(100 * time.Millisecond)
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	resp, err := client.Get("https://localhost:8087")
+	if err != nil {
+		t.Fatalf("Failed to make HTTPS request: %v", err)
+	}
+	resp.Body.Close()
+	if resp.TLS == nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 over TLS, got %d (TLS: %v)", resp.StatusCode, resp.TLS != nil)
+	}
+
+	if err := engine.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Errorf("Expected RunTLS to return nil after Shutdown, got %v", err)
+	}
+}
+
+// TestPreStopDelay verifies that Shutdown keeps serving new requests for
+// PreStopDelay before it starts draining
+func TestPreStopDelay(t *testing.T) {
+	config := DefaultConfig()
+	config.Port = ":8088"
+	config.PreStopDelay = 300 * time.Millisecond
+	engine := NewWithConfig(config)
+	engine.GET("/", echoHandler)
+
+	go engine.Run()
+	time.Sleep(100 * time.Millisecond)
+
+	begin := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- engine.Shutdown(context.Background()) }()
+	time.Sleep(100 * time.Millisecond)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://localhost:8088")
+	if err != nil {
+		t.Fatalf("Expected request during pre-stop delay to succeed: %v", err)
+	}
+	resp.Body.Close()
+	select {
+	case <-engine.ServerClosing():
+		t.Errorf("Expected ServerClosing to stay open during pre-stop delay")
+	default:
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed < config.PreStopDelay {
+		t.Errorf("Expected Shutdown to wait at least %v, took %v", config.PreStopDelay, elapsed)
+	}
+}
+
+// TestOnShutdown verifies that shutdown hooks run in reverse order with the
+// Shutdown context, and that their errors are joined without skipping hooks
+func TestOnShutdown(t *testing.T) {
+	type ctxKey struct{}
+	engine := New()
+
+	var order []string
+	hook := func(name string, err error) func(context.Context) error {
+		return func(ctx context.Context) error {
+			if ctx.Value(ctxKey{}) != "shutdown" {
+				t.Errorf("Expected hook %s to receive the Shutdown context", name)
+			}
+			order = append(order, name)
+			return err
+		}
+	}
+	dbErr := errors.New("db close failed")
+	cacheErr := errors.New("cache flush failed")
+	engine.OnShutdown(hook("db", dbErr))
+	engine.OnShutdown(hook("cache", cacheErr))
+	engine.OnShutdown(hook("logger", nil))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "shutdown")
+	err := engine.Shutdown(ctx)
+	if got := strings.Join(order, " "); got != "logger cache db" {
+		t.Errorf("Expected hooks in reverse order, got %q", got)
+	}
+	if !errors.Is(err, dbErr) || !errors.Is(err, cacheErr) {
+		t.Errorf("Expected both hook errors to be returned, got %v", err)
+	}
+}
+
+// TestRunUntil verifies that the server runs until the stop context is
+// done, then shuts down and returns the shutdown hook errors
+func TestRunUntil(t *testing.T) {
+	config := DefaultConfig()
+	config.Port = ":8089"
+	engine := NewWithConfig(config)
+	engine.GET("/", echoHandler)
+	hookErr := errors.New("hook failed")
+	engine.OnShutdown(func(ctx context.Context) error { return hookErr })
+
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- engine.runUntil(ctx, time.Second) }()
+	time.Sleep(100 * time.Millisecond)
+
+	client := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	resp, err := client.Get("http://localhost:8089")
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	resp.Body.Close()
+
+	stop()
+	if err := <-done; !errors.Is(err, hookErr) {
+		t.Errorf("Expected the hook error after stopping, got %v", err)
+	}
+	select {
+	case <-engine.ServerClosing():
+	default:
+		t.Errorf("Expected ServerClosing to be closed after stopping")
+	}
+
+	// A server that cannot start returns without waiting for a signal
+	blocker, err := net.Listen("tcp", ":8089")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer blocker.Close()
+	engine = NewWithConfig(config)
+	if err := engine.runUntil(context.Background(), time.Second); err == nil {
+		t.Errorf("Expected a listen error, got nil")
+	}
+}
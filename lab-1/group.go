@@ -0,0 +1,138 @@
+package goexpress
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Group is a set of routes that share a path prefix and middleware.
+type Group struct {
+	engine      *Engine
+	parent      *Group
+	prefix      string
+	middleware  []Middleware
+	contentType string
+	// disabled is set for groups created by GroupIf with a false condition
+	// and their nested groups.
+	disabled bool
+}
+
+// Group returns a route group whose routes are registered under prefix.
+// It panics if prefix does not begin with "/".
+func (e *Engine) Group(prefix string) *Group {
+	return newGroup(e, nil, prefix)
+}
+
+// GroupIf is like Group, but routes registered on the returned group, or
+// on groups nested in it, take effect only if condition is true:
+//
+//	debug := app.GroupIf(env == "dev", "/debug")
+//	debug.GET("/routes", listRoutes)
+//
+// If condition is false, requests to those paths get the usual 404
+// response. Paths are validated either way.
+func (e *Engine) GroupIf(condition bool, prefix string) *Group {
+	g := newGroup(e, nil, prefix)
+	g.disabled = !condition
+	return g
+}
+
+// Group returns a nested group whose prefix is appended to the prefix of g
+// and whose routes also run the middleware of g.
+func (g *Group) Group(prefix string) *Group {
+	return newGroup(g.engine, g, prefix)
+}
+
+// GroupIf is like Group, but routes of the nested group take effect only if
+// condition is true and g is enabled, see Engine.GroupIf.
+func (g *Group) GroupIf(condition bool, prefix string) *Group {
+	nested := newGroup(g.engine, g, prefix)
+	nested.disabled = nested.disabled || !condition
+	return nested
+}
+
+func newGroup(e *Engine, parent *Group, prefix string) *Group {
+	if !strings.HasPrefix(prefix, "/") {
+		panic("goexpress: group prefix must begin with '/': " + prefix)
+	}
+	g := &Group{engine: e, parent: parent, prefix: strings.TrimRight(prefix, "/")}
+	if parent != nil {
+		g.prefix = parent.prefix + g.prefix
+		g.disabled = parent.disabled
+	}
+	return g
+}
+
+// Use appends middleware to the group. It applies only to routes
+// registered on the group or its nested groups, and runs inside the
+// engine's global middleware and the middleware of enclosing groups, in
+// registration order. Like Engine.Use, it must not be called while the
+// engine is serving requests.
+func (g *Group) Use(middleware ...Middleware) {
+	g.middleware = append(g.middleware, middleware...)
+}
+
+// DefaultContentType sets the Content-Type of responses from routes of the
+// group and its nested groups that write a body without setting one, such
+// as raw writes to c.Writer or c.Data with an empty content type. Helpers
+// that pick their own type, like c.JSON or c.String, and handlers that set
+// the header themselves override it. A nested group or a route can set its
+// own default with DefaultContentType.
+func (g *Group) DefaultContentType(contentType string) {
+	g.contentType = contentType
+}
+
+// wrap returns handler wrapped with the middleware of g and its parents,
+// outermost group first. It returns handler unchanged for a nil group.
+func (g *Group) wrap(handler HandlerFunc) HandlerFunc {
+	for ; g != nil; g = g.parent {
+		handler = chain(handler, g.middleware)
+	}
+	return handler
+}
+
+// handle registers handler for method at the group prefix followed by path.
+// An empty path registers the prefix itself; any other path must begin
+// with '/'. On a disabled group the route is validated but not registered.
+func (g *Group) handle(method, path string, handler HandlerFunc) *Route {
+	if path != "" && !strings.HasPrefix(path, "/") {
+		panic("goexpress: path must begin with '/': " + path)
+	}
+	full := g.prefix + path
+	if full == "" {
+		full = "/"
+	}
+	var rt *Route
+	if g.disabled {
+		rt = newRoute(method, full, handler)
+	} else {
+		rt = g.engine.addRoute(method, full, handler)
+	}
+	rt.group = g
+	return rt
+}
+
+// GET registers a handler for GET requests to path within the group.
+func (g *Group) GET(path string, handler HandlerFunc) *Route {
+	return g.handle(http.MethodGet, path, handler)
+}
+
+// POST registers a handler for POST requests to path within the group.
+func (g *Group) POST(path string, handler HandlerFunc) *Route {
+	return g.handle(http.MethodPost, path, handler)
+}
+
+// PUT registers a handler for PUT requests to path within the group.
+func (g *Group) PUT(path string, handler HandlerFunc) *Route {
+	return g.handle(http.MethodPut, path, handler)
+}
+
+// PATCH registers a handler for PATCH requests to path within the group.
+func (g *Group) PATCH(path string, handler HandlerFunc) *Route {
+	return g.handle(http.MethodPatch, path, handler)
+}
+
+// DELETE registers a handler for DELETE requests to path within the group.
+func (g *Group) DELETE(path string, handler HandlerFunc) *Route {
+	return g.handle(http.MethodDelete, path, handler)
+}
@@ -0,0 +1,204 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGroupPrefixes verifies that groups and nested groups prefix their routes
+func TestGroupPrefixes(t *testing.T) {
+	engine := New()
+	api := engine.Group("/api")
+	api.GET("", echoHandler)
+	v1 := api.Group("/v1/")
+	v1.GET("/users", echoHandler)
+	v1.POST("/users", echoHandler)
+	v1.GET("/users/:id", func(c *Context) {
+		c.String(http.StatusOK, "user %s", c.Param("id"))
+	})
+	admin := api.Group("/admin")
+	admin.DELETE("/stats", echoHandler)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/api", http.StatusOK, "You requested: GET /api\n"},
+		{http.MethodGet, "/api/v1/users", http.StatusOK, "You requested: GET /api/v1/users\n"},
+		{http.MethodPost, "/api/v1/users", http.StatusOK, "You requested: POST /api/v1/users\n"},
+		{http.MethodGet, "/api/v1/users/7", http.StatusOK, "user 7"},
+		{http.MethodDelete, "/api/admin/stats", http.StatusOK, "You requested: DELETE /api/admin/stats\n"},
+		{http.MethodGet, "/v1/users", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+		if rr.Code != tt.status || rr.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %d %q, got %d %q", tt.method, tt.path, tt.status, tt.body, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+// TestGroupPathWithoutSlash verifies that a group route path must begin
+// with '/' instead of being glued onto the prefix
+func TestGroupPathWithoutSlash(t *testing.T) {
+	for _, disabled := range []bool{false, true} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for a path without leading slash (disabled=%v)", disabled)
+				}
+			}()
+			New().GroupIf(!disabled, "/api").GET("users", echoHandler)
+		}()
+	}
+}
+
+// TestGroupMiddleware verifies that group middleware is inherited by nested
+// groups, runs inside global middleware, and does not leak to other routes
+func TestGroupMiddleware(t *testing.T) {
+	var trace []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) {
+				trace = append(trace, name)
+				next(c)
+			}
+		}
+	}
+
+	engine := New()
+	engine.Use(record("global"))
+	api := engine.Group("/api")
+	api.Use(record("api"))
+	v1 := api.Group("/v1")
+	v1.GET("/users", func(c *Context) { trace = append(trace, "handler") })
+	v1.Use(record("v1"))
+	admin := api.Group("/admin")
+	admin.Use(record("admin"))
+	admin.GET("/stats", func(c *Context) { trace = append(trace, "handler") })
+	engine.GET("/health", func(c *Context) { trace = append(trace, "handler") })
+
+	tests := []struct {
+		path  string
+		trace string
+	}{
+		{"/api/v1/users", "global api v1 handler"},
+		{"/api/admin/stats", "global api admin handler"},
+		{"/health", "global handler"},
+		{"/api/missing", "global"},
+	}
+	for _, tt := range tests {
+		trace = nil
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if got := strings.Join(trace, " "); got != tt.trace {
+			t.Errorf("%s: expected %q, got %q", tt.path, tt.trace, got)
+		}
+	}
+}
+
+// TestDefaultContentType verifies that group and route content type defaults
+// apply to responses without a Content-Type and yield to explicit ones
+func TestDefaultContentType(t *testing.T) {
+	engine := New()
+	api := engine.Group("/api")
+	api.DefaultContentType("application/json")
+	api.GET("/raw", func(c *Context) {
+		c.Writer.Write([]byte(`{"ok":true}`))
+	})
+	api.GET("/data", func(c *Context) {
+		c.Data(http.StatusOK, "", []byte(`{"ok":true}`))
+	})
+	api.GET("/text", func(c *Context) {
+		c.String(http.StatusOK, "plain")
+	})
+	api.GET("/csv", func(c *Context) {
+		c.Writer.Header().Set("Content-Type", "text/csv")
+		c.Writer.Write([]byte("a,b"))
+	})
+	api.GET("/yaml", func(c *Context) {
+		c.Writer.Write([]byte("ok: true"))
+	}).DefaultContentType("application/yaml")
+	api.DELETE("/raw", func(c *Context) {
+		c.NoContent()
+	})
+	api.Group("/v1").GET("/raw", func(c *Context) {
+		c.Writer.Write([]byte(`{}`))
+	})
+	engine.GET("/raw", func(c *Context) {
+		c.Data(http.StatusOK, "", []byte("<html></html>"))
+	})
+
+	tests := []struct {
+		method      string
+		path        string
+		contentType string
+	}{
+		{http.MethodGet, "/api/raw", "application/json"},
+		{http.MethodGet, "/api/data", "application/json"},
+		{http.MethodGet, "/api/text", "text/plain; charset=utf-8"},
+		{http.MethodGet, "/api/csv", "text/csv"},
+		{http.MethodGet, "/api/yaml", "application/yaml"},
+		{http.MethodDelete, "/api/raw", ""},
+		{http.MethodGet, "/api/v1/raw", "application/json"},
+		{http.MethodGet, "/raw", "text/html; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := rr.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s %s: expected Content-Type %q, got %q", tt.method, tt.path, tt.contentType, got)
+		}
+	}
+}
+
+// TestConditionalRegistration verifies that GETIf and GroupIf register
+// routes only when their condition holds
+func TestConditionalRegistration(t *testing.T) {
+	for _, dev := range []bool{true, false} {
+		engine := New()
+		engine.GETIf(dev, "/fixtures", echoHandler)
+		engine.GETIf(true, "/health", echoHandler)
+		debug := engine.GroupIf(dev, "/debug")
+		debug.GET("/routes", echoHandler)
+		debug.Group("/pprof").GET("/heap", echoHandler)
+		api := engine.Group("/api")
+		api.GroupIf(dev, "/test").POST("/reset", echoHandler).When(func(r *http.Request) bool { return true })
+		api.GET("/users", echoHandler)
+
+		want := http.StatusNotFound
+		if dev {
+			want = http.StatusOK
+		}
+		tests := []struct {
+			method string
+			path   string
+			status int
+		}{
+			{http.MethodGet, "/fixtures", want},
+			{http.MethodGet, "/debug/routes", want},
+			{http.MethodGet, "/debug/pprof/heap", want},
+			{http.MethodPost, "/api/test/reset", want},
+			{http.MethodGet, "/health", http.StatusOK},
+			{http.MethodGet, "/api/users", http.StatusOK},
+		}
+		for _, tt := range tests {
+			rr := httptest.NewRecorder()
+			engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+			if rr.Code != tt.status {
+				t.Errorf("dev=%v %s %s: expected status %d, got %d", dev, tt.method, tt.path, tt.status, rr.Code)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected invalid path to panic even when not registered")
+		}
+	}()
+	New().GETIf(false, "missing-slash", echoHandler)
+}
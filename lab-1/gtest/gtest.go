@@ -0,0 +1,77 @@
+// Package gtest provides assertion helpers for testing goexpress handlers
+// in process with httptest.ResponseRecorder:
+//
+//	rr := gtest.Do(app, http.MethodGet, "/users/1", nil)
+//	gtest.AssertStatus(t, rr, http.StatusOK)
+//	gtest.AssertHeader(t, rr, "Content-Type", "application/json")
+//	gtest.AssertJSON(t, rr, &User{ID: 1, Name: "Ada"})
+//
+// Failed assertions are reported with t.Errorf, so a test reports every
+// mismatch of a response rather than stopping at the first.
+package gtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// Do serves a request for method and target with body, which may be nil,
+// through h without opening a network connection and returns the recorded
+// response.
+func Do(h http.Handler, method, target string, body io.Reader) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(method, target, body))
+	return rr
+}
+
+// AssertStatus reports an error if the response status is not want.
+func AssertStatus(t testing.TB, rr *httptest.ResponseRecorder, want int) {
+	t.Helper()
+	if rr.Code != want {
+		t.Errorf("Expected status %d, got %d", want, rr.Code)
+	}
+}
+
+// AssertHeader reports an error if the first value of the named response
+// header is not want. An empty want asserts that the header is absent.
+func AssertHeader(t testing.TB, rr *httptest.ResponseRecorder, name, want string) {
+	t.Helper()
+	if got := rr.Header().Get(name); got != want {
+		t.Errorf("Expected header %s %q, got %q", name, want, got)
+	}
+}
+
+// AssertBody reports an error if the response body is not want.
+func AssertBody(t testing.TB, rr *httptest.ResponseRecorder, want string) {
+	t.Helper()
+	if got := rr.Body.String(); got != want {
+		t.Errorf("Expected body %q, got %q", want, got)
+	}
+}
+
+// AssertJSON decodes the response body into a new value of the type
+// expected points to and reports an error if it is not deeply equal to
+// *expected, or if the body is not valid JSON. Decoding into the same
+// type lets struct tags, omitted fields and number types be compared as
+// the handler intended rather than as raw text.
+func AssertJSON(t testing.TB, rr *httptest.ResponseRecorder, expected interface{}) {
+	t.Helper()
+	want := reflect.ValueOf(expected)
+	if want.Kind() != reflect.Pointer || want.IsNil() {
+		t.Errorf("AssertJSON: expected must be a non-nil pointer, got %T", expected)
+		return
+	}
+
+	got := reflect.New(want.Elem().Type())
+	if err := json.Unmarshal(rr.Body.Bytes(), got.Interface()); err != nil {
+		t.Errorf("Expected JSON body, got %q: %v", rr.Body.String(), err)
+		return
+	}
+	if !reflect.DeepEqual(got.Elem().Interface(), want.Elem().Interface()) {
+		t.Errorf("Expected JSON %+v, got %+v", want.Elem().Interface(), got.Elem().Interface())
+	}
+}
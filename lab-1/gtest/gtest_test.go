@@ -0,0 +1,53 @@
+package gtest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Ridwan414/goexpress"
+)
+
+// recordingT captures assertion failures instead of failing the test
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// TestAssertions verifies that the helpers pass on matching responses and
+// report each mismatch otherwise
+func TestAssertions(t *testing.T) {
+	app := goexpress.New()
+	app.GET("/users/:id", func(c *goexpress.Context) {
+		c.JSON(http.StatusOK, user{ID: 1, Name: "Ada"})
+	})
+	rr := Do(app, http.MethodGet, "/users/1", nil)
+
+	AssertStatus(t, rr, http.StatusOK)
+	AssertHeader(t, rr, "Content-Type", "application/json")
+	AssertHeader(t, rr, "X-Missing", "")
+	AssertBody(t, rr, `{"id":1,"name":"Ada"}`)
+	AssertJSON(t, rr, &user{ID: 1, Name: "Ada"})
+
+	rec := &recordingT{TB: t}
+	AssertStatus(rec, rr, http.StatusCreated)
+	AssertHeader(rec, rr, "Content-Type", "text/plain")
+	AssertBody(rec, rr, "{}")
+	AssertJSON(rec, rr, &user{ID: 2, Name: "Ada"})
+	AssertJSON(rec, rr, user{})
+	AssertJSON(rec, Do(app, http.MethodGet, "/missing", nil), &user{})
+	if len(rec.errors) != 6 {
+		t.Errorf("Expected 6 reported failures, got %d: %q", len(rec.errors), rec.errors)
+	}
+}
@@ -0,0 +1,50 @@
+package goexpress
+
+import (
+	"log"
+	"net/http"
+	"strings"
+)
+
+// MaxQueryParams returns a middleware that rejects requests carrying more
+// than n query parameters with 400 Bad Request, before anything parses the
+// query string. Parameters are counted as '&'-separated pairs of the raw
+// query, so repeated keys each count.
+func MaxQueryParams(n int) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if raw := c.Request.URL.RawQuery; raw != "" && strings.Count(raw, "&")+1 > n {
+				c.Error(NewHTTPError(http.StatusBadRequest, "too many query parameters"))
+				return
+			}
+			next(c)
+		}
+	}
+}
+
+// SafeMethodGuard returns a development middleware that logs a warning when
+// a GET or HEAD handler does something that contradicts the safe-method
+// semantics of RFC 9110: setting a cookie, or responding 201 Created or
+// 202 Accepted, which report that a resource was created or work was
+// queued. Such side effects in read endpoints are usually accidental and
+// break caching, prefetching and retries. The checks run only when
+// Config.Debug is set; otherwise the middleware does nothing. It cannot see
+// mutations that leave no trace in the response, such as database writes.
+func SafeMethodGuard() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			next(c)
+
+			method := c.Request.Method
+			if c.engine == nil || !c.engine.config.Debug || (method != http.MethodGet && method != http.MethodHead) {
+				return
+			}
+			if cookies := c.Writer.Header().Values("Set-Cookie"); len(cookies) > 0 {
+				log.Printf("[SafeMethodGuard] %s %s set %d cookie(s)\n", method, c.Request.URL.Path, len(cookies))
+			}
+			if status := c.Status(); status == http.StatusCreated || status == http.StatusAccepted {
+				log.Printf("[SafeMethodGuard] %s %s responded %d %s\n", method, c.Request.URL.Path, status, http.StatusText(status))
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package goexpress
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestMaxQueryParams verifies that requests over the query parameter limit get 400
+func TestMaxQueryParams(t *testing.T) {
+	engine := New()
+	engine.Use(MaxQueryParams(3))
+	engine.GET("/search", echoHandler)
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/search", http.StatusOK},
+		{"/search?q=go&page=2&sort=asc", http.StatusOK},
+		{"/search?q=go&page=2&sort=asc&limit=10", http.StatusBadRequest},
+		{"/search?tag=a&tag=b&tag=c&tag=d", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.target, tt.status, rr.Code)
+		}
+	}
+
+	// Rejections go through the error renderer like any other error
+	engine.SetErrorRenderer(ProblemJSON)
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/search?a=1&b=2&c=3&d=4", nil))
+	if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Expected Content-Type application/problem+json, got %q", ct)
+	}
+	if !strings.Contains(rr.Body.String(), `"detail":"too many query parameters"`) {
+		t.Errorf("Expected problem detail in body, got %q", rr.Body.String())
+	}
+}
+
+// TestSafeMethodGuard verifies that GET and HEAD handlers setting cookies or
+// reporting creation are logged in debug mode only
+func TestSafeMethodGuard(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	handlers := func(engine *Engine) {
+		engine.Use(SafeMethodGuard())
+		engine.GET("/login", func(c *Context) {
+			http.SetCookie(c.Writer, &http.Cookie{Name: "session", Value: "abc"})
+		})
+		engine.GET("/jobs", func(c *Context) {
+			c.Writer.WriteHeader(http.StatusAccepted)
+		})
+		engine.GET("/items", echoHandler)
+		engine.POST("/items", func(c *Context) {
+			http.SetCookie(c.Writer, &http.Cookie{Name: "session", Value: "abc"})
+			c.Writer.WriteHeader(http.StatusCreated)
+		})
+	}
+
+	config := DefaultConfig()
+	config.Debug = true
+	engine := NewWithConfig(config)
+	handlers(engine)
+
+	tests := []struct {
+		method string
+		path   string
+		log    string
+	}{
+		{http.MethodGet, "/login", "[SafeMethodGuard] GET /login set 1 cookie(s)\n"},
+		{http.MethodGet, "/jobs", "[SafeMethodGuard] GET /jobs responded 202 Accepted\n"},
+		{http.MethodGet, "/items", ""},
+		{http.MethodPost, "/items", ""},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if got := buf.String(); !strings.HasSuffix(got, tt.log) || (tt.log == "" && got != "") {
+			t.Errorf("%s %s: expected log %q, got %q", tt.method, tt.path, tt.log, got)
+		}
+	}
+
+	engine = New()
+	handlers(engine)
+	buf.Reset()
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/login", nil))
+	if buf.Len() != 0 {
+		t.Errorf("Expected no warnings outside debug mode, got %q", buf.String())
+	}
+}
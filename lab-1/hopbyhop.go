@@ -0,0 +1,64 @@
+package goexpress
+
+import (
+	"net/http"
+	"strings"
+)
+
+// hopByHopHeaders are the headers RFC 7230 section 6.1 defines as
+// meaningful only for a single connection, plus the non-standard
+// Proxy-Connection still sent by some clients.
+var hopByHopHeaders = []string{
+	"Connection",
+	"Proxy-Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// StripHopByHop returns a middleware that removes hop-by-hop headers from
+// the request before the handler sees it, so handlers that forward
+// r.Header to another server do not pass on headers that only applied to
+// the client's connection. Besides the fixed list from RFC 7230, every
+// header named in the Connection header is removed. "Te: trailers" is
+// kept, since it is forwarded to signal trailer support. Because Upgrade
+// is removed, routes that accept WebSocket or other protocol upgrades
+// must not use it.
+func StripHopByHop() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			stripHopByHop(c.Request.Header)
+			next(c)
+		}
+	}
+}
+
+// stripHopByHop removes the hop-by-hop headers from h in place.
+func stripHopByHop(h http.Header) {
+	for _, value := range h["Connection"] {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				h.Del(name)
+			}
+		}
+	}
+
+	trailers := false
+	for _, value := range h["Te"] {
+		for _, name := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(name), "trailers") {
+				trailers = true
+			}
+		}
+	}
+	for _, name := range hopByHopHeaders {
+		h.Del(name)
+	}
+	if trailers {
+		h.Set("Te", "trailers")
+	}
+}
@@ -0,0 +1,44 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStripHopByHop verifies that hop-by-hop headers, including those named
+// in Connection, are removed before the handler while end-to-end headers stay
+func TestStripHopByHop(t *testing.T) {
+	var got http.Header
+	engine := New()
+	engine.Use(StripHopByHop())
+	engine.GET("/", func(c *Context) {
+		got = c.Request.Header.Clone()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Connection", "keep-alive, X-Session-Hint")
+	req.Header.Set("X-Session-Hint", "abc")
+	req.Header.Set("Keep-Alive", "timeout=5")
+	req.Header.Set("Proxy-Connection", "keep-alive")
+	req.Header.Set("Proxy-Authorization", "Basic Zm9vOmJhcg==")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Te", "gzip, trailers")
+	req.Header.Set("Trailer", "Expires")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	for _, name := range []string{"Connection", "X-Session-Hint", "Keep-Alive", "Proxy-Connection",
+		"Proxy-Authorization", "Upgrade", "Trailer"} {
+		if v := got.Get(name); v != "" {
+			t.Errorf("Expected %s to be stripped, got %q", name, v)
+		}
+	}
+	if v := got.Get("Te"); v != "trailers" {
+		t.Errorf("Expected Te to be reduced to trailers, got %q", v)
+	}
+	if got.Get("Accept") != "application/json" || got.Get("Authorization") != "Bearer token" {
+		t.Errorf("Expected end-to-end headers to be kept, got %v", got)
+	}
+}
@@ -0,0 +1,87 @@
+package goexpress
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// checkHost validates the Host header of r against the configuration.
+// Malformed hosts are rejected with 400 Bad Request and hosts that are not
+// listed in Config.AllowedHosts with 421 Misdirected Request. Accepted
+// hosts are normalized in place, so r.Host is lowercased, without port or
+// trailing dot, and IPv6 addresses keep their brackets, as in "[::1]". It
+// reports whether the request may continue.
+func (e *Engine) checkHost(w http.ResponseWriter, r *http.Request) bool {
+	if r.Host == "" && len(e.config.AllowedHosts) == 0 {
+		// HTTP/1.0 clients may omit Host; only an allow-list requires it
+		return true
+	}
+
+	host, ok := normalizeHost(r.Host)
+	if !ok {
+		http.Error(w, "invalid Host header", http.StatusBadRequest)
+		return false
+	}
+
+	allowed := len(e.config.AllowedHosts) == 0
+	for _, h := range e.config.AllowedHosts {
+		if allowedHost, ok := normalizeHost(h); ok && allowedHost == host {
+			allowed = true
+			break
+		}
+	}
+	if allowed {
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		r.Host = host
+		return true
+	}
+	http.Error(w, http.StatusText(http.StatusMisdirectedRequest), http.StatusMisdirectedRequest)
+	return false
+}
+
+// normalizeHost strips the port from a Host header value, lowercases it and
+// removes a trailing dot. It reports false if the result is empty or contains
+// characters that are not valid in a hostname or IP address.
+func normalizeHost(host string) (string, bool) {
+	if h, port, err := net.SplitHostPort(host); err == nil {
+		if !isDigits(port) {
+			return "", false
+		}
+		host = h
+	} else if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", false
+	}
+
+	if net.ParseIP(host) != nil {
+		return host, true
+	}
+	for i := 0; i < len(host); i++ {
+		c := host[i]
+		switch {
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_':
+		default:
+			return "", false
+		}
+	}
+	return host, true
+}
+
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
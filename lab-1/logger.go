@@ -0,0 +1,36 @@
+package goexpress
+
+import (
+	"io"
+	"log"
+	"time"
+)
+
+// Logger returns a middleware that logs the method, path, status code and
+// latency of every request through the standard log package, alongside the
+// server's own start and shutdown messages. The ID set by the RequestID
+// middleware is appended when present.
+func Logger() Middleware {
+	return logRequests(log.Default())
+}
+
+// LoggerWithWriter returns a middleware like Logger that writes to out.
+func LoggerWithWriter(out io.Writer) Middleware {
+	return logRequests(log.New(out, "", log.LstdFlags))
+}
+
+func logRequests(logger *log.Logger) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			start := time.Now()
+			next(c)
+			if id := c.RequestID(); id != "" {
+				logger.Printf("[GoExpress] %3d | %13v | %-7s %s | %s\n",
+					c.Status(), time.Since(start), c.Request.Method, c.Request.URL.Path, id)
+				return
+			}
+			logger.Printf("[GoExpress] %3d | %13v | %-7s %s\n",
+				c.Status(), time.Since(start), c.Request.Method, c.Request.URL.Path)
+		}
+	}
+}
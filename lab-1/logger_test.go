@@ -0,0 +1,40 @@
+package goexpress
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+// TestLogger verifies that the logger records method, path, status and latency,
+// including the implicit 200 and unmatched 404 responses
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	engine := New()
+	engine.Use(LoggerWithWriter(&buf))
+	engine.GET("/implicit", func(c *Context) {
+		c.Writer.Write([]byte("ok"))
+	})
+	engine.POST("/users", func(c *Context) {
+		c.String(http.StatusCreated, "created")
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		line   string
+	}{
+		{http.MethodGet, "/implicit", `\[GoExpress\] 200 \| +\S+ \| GET +/implicit`},
+		{http.MethodPost, "/users", `\[GoExpress\] 201 \| +\S+ \| POST +/users`},
+		{http.MethodGet, "/missing", `\[GoExpress\] 404 \| +\S+ \| GET +/missing`},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+		if !regexp.MustCompile(tt.line).MatchString(buf.String()) {
+			t.Errorf("%s %s: expected log line matching %q, got %q", tt.method, tt.path, tt.line, buf.String())
+		}
+	}
+}
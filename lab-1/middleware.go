@@ -0,0 +1,31 @@
+package goexpress
+
+// Middleware wraps a HandlerFunc with behaviour that runs before and/or
+// after it, such as logging or request guards. Apply one to every request
+// with Engine.Use, or to a single route by wrapping its handler:
+// app.POST("/upload", mw(upload)).
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Use appends middleware to the global chain, which wraps every request
+// the engine handles, including requests that match no route and end in
+// 404 Not Found. Middleware runs in registration order: the first one
+// registered is the outermost, so it runs first before the handler and
+// last after it. For app.Use(a, b) followed by app.Use(c) the order is
+//
+//	a → b → c → handler → c → b → a
+//
+// Requests delegated to a mounted engine run this chain first and that
+// engine's own middleware inside it. Use must not be called while the
+// engine is serving requests.
+func (e *Engine) Use(middleware ...Middleware) {
+	e.middleware = append(e.middleware, middleware...)
+}
+
+// chain wraps handler with middleware so that the first middleware runs
+// outermost.
+func chain(handler HandlerFunc, middleware []Middleware) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+	return handler
+}
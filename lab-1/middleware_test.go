@@ -0,0 +1,70 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMiddlewareOrder verifies that global middleware runs in registration
+// order around the handler, and also runs for unmatched requests
+func TestMiddlewareOrder(t *testing.T) {
+	var trace []string
+	record := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c *Context) {
+				trace = append(trace, name+".before")
+				next(c)
+				trace = append(trace, name+".after")
+			}
+		}
+	}
+
+	engine := New()
+	engine.Use(record("a"), record("b"))
+	engine.GET("/", func(c *Context) {
+		trace = append(trace, "handler")
+	})
+	engine.Use(record("c"))
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	want := "a.before b.before c.before handler c.after b.after a.after"
+	if got := strings.Join(trace, " "); got != want {
+		t.Errorf("Expected order %q, got %q", want, got)
+	}
+
+	trace = nil
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", rr.Code)
+	}
+	want = "a.before b.before c.before c.after b.after a.after"
+	if got := strings.Join(trace, " "); got != want {
+		t.Errorf("Expected middleware to observe the 404 as %q, got %q", want, got)
+	}
+}
+
+// TestMiddlewareShortCircuit verifies that a middleware can stop the chain
+func TestMiddlewareShortCircuit(t *testing.T) {
+	engine := New()
+	engine.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if c.Request.Header.Get("Authorization") == "" {
+				c.String(http.StatusUnauthorized, "unauthorized")
+				return
+			}
+			next(c)
+		}
+	})
+	engine.GET("/secret", func(c *Context) {
+		c.String(http.StatusOK, "secret")
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/secret", nil))
+	if rr.Code != http.StatusUnauthorized || rr.Body.String() != "unauthorized" {
+		t.Errorf("Expected 401 unauthorized, got %d %q", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,70 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// mount is a sub-engine attached under a path prefix.
+type mount struct {
+	prefix string
+	engine *Engine
+}
+
+// MountEngine delegates every request whose path is prefix, or starts with
+// prefix followed by "/", to sub with the prefix stripped from the path.
+// sub handles those requests with its own handlers and middleware, inside
+// the global middleware of e. Server-level settings (port, timeouts, URI
+// length, allowed hosts and connection limits) are taken from e, because
+// the server of sub is never started. When prefixes overlap the longest
+// one wins.
+//
+// MountEngine panics if prefix does not begin with "/", if sub is nil or e,
+// or if prefix is already mounted.
+func (e *Engine) MountEngine(prefix string, sub *Engine) {
+	if !strings.HasPrefix(prefix, "/") {
+		panic("goexpress: mount prefix must begin with '/': " + prefix)
+	}
+	if sub == nil || sub == e {
+		panic("goexpress: invalid engine mounted at " + prefix)
+	}
+	prefix = strings.TrimRight(prefix, "/")
+	for _, m := range e.mounts {
+		if m.prefix == prefix {
+			panic("goexpress: prefix already mounted: " + prefix)
+		}
+	}
+
+	e.mounts = append(e.mounts, mount{prefix: prefix, engine: sub})
+	sort.SliceStable(e.mounts, func(i, j int) bool {
+		return len(e.mounts[i].prefix) > len(e.mounts[j].prefix)
+	})
+}
+
+// matchMount finds the sub-engine responsible for r and returns it along
+// with a shallow copy of r whose URL path has the mount prefix removed.
+func (e *Engine) matchMount(r *http.Request) (*Engine, *http.Request, bool) {
+	for _, m := range e.mounts {
+		rest, ok := strings.CutPrefix(r.URL.Path, m.prefix)
+		if !ok || (rest != "" && rest[0] != '/') {
+			continue
+		}
+		if rest == "" {
+			rest = "/"
+		}
+
+		req := new(http.Request)
+		*req = *r
+		req.URL = new(url.URL)
+		*req.URL = *r.URL
+		req.URL.Path = rest
+		if r.URL.RawPath != "" {
+			// Let net/url recompute the escaped form from the stripped path
+			req.URL.RawPath = ""
+		}
+		return m.engine, req, true
+	}
+	return nil, nil, false
+}
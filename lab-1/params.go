@@ -0,0 +1,27 @@
+package goexpress
+
+// params holds the path parameters captured by a matched route, with
+// names[i] corresponding to values[i].
+type params struct {
+	names  []string
+	values []string
+}
+
+// get returns the value of the named parameter, or "" if it does not exist.
+func (p *params) get(name string) string {
+	v, _ := p.lookup(name)
+	return v
+}
+
+// lookup returns the value of the named parameter and whether it exists.
+func (p *params) lookup(name string) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	for i, n := range p.names {
+		if n == name {
+			return p.values[i], true
+		}
+	}
+	return "", false
+}
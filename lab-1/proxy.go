@@ -0,0 +1,214 @@
+package goexpress
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultMaxFails is the default of Backend.MaxFails.
+	defaultMaxFails = 3
+
+	// defaultFailTimeout is the default of Backend.FailTimeout.
+	defaultFailTimeout = 10 * time.Second
+)
+
+// Backend is an upstream server of ProxyBalanced.
+type Backend struct {
+	// URL is the base URL requests are forwarded to, such as
+	// "http://10.0.0.1:8080". A path in it is prepended to the request path
+	URL string
+
+	// Weight is the share of requests the backend gets relative to the
+	// others. Defaults to 1
+	Weight int
+
+	// MaxFails is the number of consecutive failed attempts after which
+	// the backend is marked down. Defaults to 3
+	MaxFails int
+
+	// FailTimeout is how long a backend marked down gets no requests
+	// before it is tried again. Defaults to 10 seconds
+	FailTimeout time.Duration
+}
+
+// ProxyBalanced forwards every request under prefix, whatever its method,
+// to one of backends with the request path unchanged. Backends are chosen
+// by smooth weighted round-robin, so a backend with weight 3 gets three of
+// every four requests next to one with weight 1, interleaved rather than
+// in bursts. Health is tracked passively: an attempt fails when the
+// backend cannot be reached or does not answer, and after MaxFails
+// consecutive failures the backend is marked down for FailTimeout, after
+// which the next request tries it again. HTTP error responses from a
+// backend are passed through and do not count as failures. A failed
+// request without a body is retried on the next healthy backend, each
+// backend at most once; requests with a body are not, as the body has
+// already been consumed. If every attempt fails the client gets 502 Bad
+// Gateway, and 503 Service Unavailable if all backends are down. The
+// forwarding itself is done by httputil.ReverseProxy, which removes
+// hop-by-hop headers and sets X-Forwarded-For, X-Forwarded-Host and
+// X-Forwarded-Proto. It panics if backends is empty or a URL is invalid.
+func (e *Engine) ProxyBalanced(prefix string, backends []Backend) {
+	if len(backends) == 0 {
+		panic("goexpress: no backends for proxy " + prefix)
+	}
+	b := &balancer{upstreams: make([]*upstream, len(backends))}
+	for i, backend := range backends {
+		b.upstreams[i] = newUpstream(backend)
+	}
+
+	base := strings.TrimSuffix(prefix, "/")
+	for _, method := range []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	} {
+		if base != "" {
+			e.addRoute(method, base, b.serve)
+		}
+		e.addRoute(method, base+"/*path", b.serve)
+	}
+}
+
+// upstream is a Backend with its proxy and balancing state.
+type upstream struct {
+	backend Backend
+	proxy   *httputil.ReverseProxy
+
+	// current is the smooth weighted round-robin counter.
+	current int
+	// fails counts consecutive failed attempts.
+	fails int
+	// downUntil is when a backend marked down may be tried again.
+	downUntil time.Time
+}
+
+// proxyErrKey is the request context key of the error slot an attempt's
+// ReverseProxy.ErrorHandler reports to.
+type proxyErrKey struct{}
+
+func newUpstream(backend Backend) *upstream {
+	target, err := url.Parse(backend.URL)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		panic("goexpress: invalid backend URL: " + backend.URL)
+	}
+	if backend.Weight < 0 {
+		panic("goexpress: negative weight for backend " + backend.URL)
+	}
+	if backend.Weight == 0 {
+		backend.Weight = 1
+	}
+	if backend.MaxFails <= 0 {
+		backend.MaxFails = defaultMaxFails
+	}
+	if backend.FailTimeout <= 0 {
+		backend.FailTimeout = defaultFailTimeout
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if slot, ok := r.Context().Value(proxyErrKey{}).(*error); ok {
+				*slot = err
+			}
+		},
+	}
+	return &upstream{backend: backend, proxy: proxy}
+}
+
+// balancer picks upstreams for ProxyBalanced.
+type balancer struct {
+	mu        sync.Mutex
+	upstreams []*upstream
+}
+
+// serve forwards the request, failing over to the next healthy upstream
+// as long as nothing has been written and the request can be replayed.
+func (b *balancer) serve(c *Context) {
+	r := c.Request
+	replayable := r.Body == nil || r.Body == http.NoBody
+	tried := make([]bool, len(b.upstreams))
+	attempts := 0
+	for {
+		u := b.next(tried)
+		if u == nil {
+			break
+		}
+		attempts++
+
+		var err error
+		u.proxy.ServeHTTP(c.Writer, r.WithContext(context.WithValue(r.Context(), proxyErrKey{}, &err)))
+		if r.Context().Err() != nil {
+			// The client is gone, which says nothing about the backend
+			return
+		}
+		b.report(u, err)
+		if err == nil {
+			return
+		}
+		log.Printf("[Proxy] %s %s: backend %s failed: %v\n", r.Method, r.URL.Path, u.backend.URL, err)
+		if c.writer.written || !replayable {
+			break
+		}
+	}
+
+	if attempts == 0 {
+		c.Error(NewHTTPError(http.StatusServiceUnavailable, "no healthy backend"))
+		return
+	}
+	c.Error(NewHTTPError(http.StatusBadGateway, ""))
+}
+
+// next returns the healthy upstream not yet tried with the highest smooth
+// weighted round-robin counter, or nil if there is none.
+func (b *balancer) next(tried []bool) *upstream {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	var best *upstream
+	total := 0
+	for i, u := range b.upstreams {
+		if tried[i] || now.Before(u.downUntil) {
+			continue
+		}
+		u.current += u.backend.Weight
+		total += u.backend.Weight
+		if best == nil || u.current > best.current {
+			best = u
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best.current -= total
+	for i, u := range b.upstreams {
+		if u == best {
+			tried[i] = true
+		}
+	}
+	return best
+}
+
+// report records the outcome of an attempt on u, marking it down after
+// too many consecutive failures.
+func (b *balancer) report(u *upstream, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		u.fails = 0
+		return
+	}
+	u.fails++
+	if u.fails >= u.backend.MaxFails {
+		u.downUntil = time.Now().Add(u.backend.FailTimeout)
+		log.Printf("[Proxy] backend %s marked down for %v after %d failures\n", u.backend.URL, u.backend.FailTimeout, u.fails)
+	}
+}
@@ -0,0 +1,133 @@
+package goexpress
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// newBackend starts a server that answers with its name and the request
+// path, or with 500 for paths ending in /fail.
+func newBackend(t *testing.T, name string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/fail") {
+			http.Error(w, name+" failed", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(name + " " + r.URL.Path))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// TestProxyBalanced verifies that requests are spread by weight, keep their
+// path, and that backend error responses are passed through
+func TestProxyBalanced(t *testing.T) {
+	a := newBackend(t, "a")
+	b := newBackend(t, "b")
+	engine := New()
+	engine.ProxyBalanced("/api", []Backend{{URL: a.URL, Weight: 3}, {URL: b.URL}})
+
+	var order []string
+	for i := 0; i < 8; i++ {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		name, path, _ := strings.Cut(rr.Body.String(), " ")
+		if rr.Code != http.StatusOK || path != "/api/users" {
+			t.Fatalf("Expected 200 for /api/users, got %d %q", rr.Code, rr.Body.String())
+		}
+		order = append(order, name)
+	}
+	if got := strings.Join(order, ""); got != "aabaaaba" {
+		t.Errorf("Expected smooth weighted order aabaaaba, got %s", got)
+	}
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/api", nil))
+	if rr.Code != http.StatusOK || !strings.HasSuffix(rr.Body.String(), " /api") {
+		t.Errorf("Expected the bare prefix to be proxied, got %d %q", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/fail", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected the backend's 500 to be passed through, got %d", rr.Code)
+	}
+}
+
+// TestProxyBalancedFailover verifies that unreachable backends are retried
+// on the next one, marked down after MaxFails, and that requests with a
+// body are not retried
+func TestProxyBalancedFailover(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	up := newBackend(t, "up")
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	engine := New()
+	engine.ProxyBalanced("/api/", []Backend{{URL: down.URL, Weight: 2, MaxFails: 2}, {URL: up.URL}})
+
+	for i := 0; i < 6; i++ {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+		if rr.Code != http.StatusOK || rr.Body.String() != "up /api/items" {
+			t.Errorf("Request %d: expected failover to the healthy backend, got %d %q", i, rr.Code, rr.Body.String())
+		}
+	}
+	if n := strings.Count(buf.String(), "backend "+down.URL+" failed"); n != 2 {
+		t.Errorf("Expected 2 failed attempts before the backend is marked down, got %d", n)
+	}
+	if !strings.Contains(buf.String(), "[Proxy] backend "+down.URL+" marked down") {
+		t.Errorf("Expected the backend to be marked down, got %q", buf.String())
+	}
+
+	// With only unreachable backends, a request with a body fails without
+	// retry, and once all are down requests get 503
+	engine = New()
+	engine.ProxyBalanced("/api", []Backend{{URL: down.URL, MaxFails: 1}, {URL: down.URL + "/v2", MaxFails: 1}})
+	tests := []struct {
+		method string
+		body   string
+		status int
+	}{
+		{http.MethodPost, "payload", http.StatusBadGateway},
+		{http.MethodGet, "", http.StatusBadGateway},
+		{http.MethodGet, "", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, "/api/items", strings.NewReader(tt.body)))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.method, tt.status, rr.Code)
+		}
+		if tt.body != "" && strings.Count(buf.String(), "failed") != 1 {
+			t.Errorf("Expected a request with a body to be tried once, got %q", buf.String())
+		}
+	}
+}
+
+// TestProxyBalancedInvalid verifies that invalid backends panic at registration
+func TestProxyBalancedInvalid(t *testing.T) {
+	for _, backends := range [][]Backend{
+		nil,
+		{{URL: "localhost:8080"}},
+		{{URL: "http://localhost:8080", Weight: -1}},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected panic for backends %v", backends)
+				}
+			}()
+			New().ProxyBalanced("/api", backends)
+		}()
+	}
+}
@@ -0,0 +1,49 @@
+package goexpress
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoveryHandler writes the response for a request whose handler panicked
+// with the value recovered.
+type RecoveryHandler func(c *Context, recovered interface{})
+
+// Recover returns a middleware that recovers from panics in the rest of the
+// chain, logs the panic value with its stack trace and responds with
+// 500 Internal Server Error through Context.Error, so the engine's error
+// renderer and status pages apply. If the handler already wrote the
+// status, it is left as is, since it has been sent. A panic with
+// http.ErrAbortHandler is re-raised so net/http aborts the response as
+// intended.
+func Recover() Middleware {
+	return RecoverWithHandler(func(c *Context, recovered interface{}) {
+		c.Error(NewHTTPError(http.StatusInternalServerError, ""))
+	})
+}
+
+// RecoverWithHandler returns a middleware like Recover that calls handler
+// to write the response instead of the default plain-text 500. The handler
+// is only called if nothing was written before the panic.
+func RecoverWithHandler(handler RecoveryHandler) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				log.Printf("[Recovery] panic recovered: %v\n%s", rec, debug.Stack())
+				if !c.writer.written {
+					handler(c, rec)
+				}
+			}()
+			next(c)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package goexpress
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// TestRecover verifies that panics become 500 responses, are logged with a
+// stack trace, and do not override a status that was already written
+func TestRecover(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	engine := New()
+	engine.Use(Recover())
+	engine.GET("/panic", func(c *Context) {
+		panic("boom")
+	})
+	engine.GET("/partial", func(c *Context) {
+		c.String(http.StatusOK, "partial")
+		panic("late boom")
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rr.Code)
+	}
+	if !strings.Contains(buf.String(), "panic recovered: boom") || !strings.Contains(buf.String(), "goroutine") {
+		t.Errorf("Expected panic and stack trace in log, got %q", buf.String())
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/partial", nil))
+	if rr.Code != http.StatusOK || rr.Body.String() != "partial" {
+		t.Errorf("Expected the written 200 response to be kept, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+// TestRecoverWithHandler verifies that a custom handler writes the panic response
+func TestRecoverWithHandler(t *testing.T) {
+	log.SetOutput(new(bytes.Buffer))
+	defer log.SetOutput(os.Stderr)
+
+	engine := New()
+	engine.Use(RecoverWithHandler(func(c *Context, recovered interface{}) {
+		c.JSON(http.StatusInternalServerError, map[string]interface{}{"error": recovered})
+	}))
+	engine.GET("/panic", func(c *Context) {
+		panic("boom")
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rr.Code)
+	}
+	if rr.Body.String() != `{"error":"boom"}` {
+		t.Errorf("Expected JSON error body, got %q", rr.Body.String())
+	}
+}
+
+// TestRecoverAbortHandler verifies that http.ErrAbortHandler is re-raised
+func TestRecoverAbortHandler(t *testing.T) {
+	engine := New()
+	engine.Use(Recover())
+	engine.GET("/abort", func(c *Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to propagate, got %v", rec)
+		}
+	}()
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+}
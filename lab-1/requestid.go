@@ -0,0 +1,89 @@
+package goexpress
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// maxRequestIDLength is the longest incoming X-Request-ID that RequestID
+// accepts.
+const maxRequestIDLength = 128
+
+// requestIDKey is the request context key of the request ID.
+type requestIDKey struct{}
+
+// RequestID returns a middleware that gives every request an ID for
+// tracing it across services. The ID is taken from the X-Request-ID
+// header set by a proxy or calling service, or else generated as a random
+// UUID. Incoming IDs longer than 128 bytes or containing anything but
+// printable ASCII are replaced, so clients cannot inject text into logs.
+// The ID is echoed in the X-Request-ID response header and stored in the
+// request context, where Context.RequestID and RequestIDFromContext find
+// it, for example to forward it on outgoing requests. Logger includes it
+// whichever of the two is registered first, as both share the request's
+// Context. The exception is Timeout, which runs the rest of the chain with
+// a Context of its own: if RequestID is registered after Timeout, its ID
+// stays on that Context, and middleware outside Timeout, such as Logger,
+// does not see it.
+func RequestID() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			id := c.Request.Header.Get("X-Request-ID")
+			if !validRequestID(id) {
+				id = newRequestID()
+			}
+			c.Writer.Header().Set("X-Request-ID", id)
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDKey{}, id))
+			next(c)
+		}
+	}
+}
+
+// RequestID returns the ID assigned to the request by the RequestID
+// middleware, or an empty string if it does not run for this route.
+func (c *Context) RequestID() string {
+	return RequestIDFromContext(c.Request.Context())
+}
+
+// RequestIDFromContext returns the request ID stored in ctx by the
+// RequestID middleware, or an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID reports whether id is a non-empty, reasonably short
+// string of printable ASCII.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var u [16]byte
+	// crypto/rand.Read never fails on supported platforms
+	rand.Read(u[:])
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
@@ -0,0 +1,88 @@
+package goexpress
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestRequestID verifies that incoming IDs are kept when valid, replaced
+// by a UUID otherwise, and exposed on the context and response header
+func TestRequestID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	engine := New()
+	engine.Use(RequestID())
+	engine.GET("/", func(c *Context) {
+		if got := RequestIDFromContext(c.Request.Context()); got != c.RequestID() {
+			t.Errorf("Expected RequestIDFromContext %q, got %q", c.RequestID(), got)
+		}
+		c.String(http.StatusOK, "%s", c.RequestID())
+	})
+
+	tests := []struct {
+		incoming string
+		keep     bool
+	}{
+		{"", false},
+		{"abc-123", true},
+		{"trace\nforged log line", false},
+		{"id with spaces", false},
+		{strings.Repeat("a", 129), false},
+	}
+	seen := make(map[string]bool)
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header["X-Request-Id"] = []string{tt.incoming}
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+
+		id := rr.Header().Get("X-Request-ID")
+		if rr.Body.String() != id {
+			t.Errorf("Incoming %q: expected c.RequestID %q, got %q", tt.incoming, id, rr.Body.String())
+		}
+		if tt.keep && id != tt.incoming {
+			t.Errorf("Incoming %q: expected it to be kept, got %q", tt.incoming, id)
+		}
+		if !tt.keep && !uuid.MatchString(id) {
+			t.Errorf("Incoming %q: expected a generated UUID, got %q", tt.incoming, id)
+		}
+		if seen[id] {
+			t.Errorf("Expected unique IDs, got %q twice", id)
+		}
+		seen[id] = true
+	}
+
+	// Without the middleware there is no ID
+	engine = New()
+	engine.GET("/", func(c *Context) {
+		if id := c.RequestID(); id != "" {
+			t.Errorf("Expected no request ID, got %q", id)
+		}
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// TestLoggerRequestID verifies that Logger appends the request ID in
+// either registration order
+func TestLoggerRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	for _, order := range [][]Middleware{
+		{LoggerWithWriter(&buf), RequestID()},
+		{RequestID(), LoggerWithWriter(&buf)},
+	} {
+		buf.Reset()
+		engine := New()
+		engine.Use(order...)
+		engine.GET("/", echoHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", "req-42")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+		if !strings.HasSuffix(buf.String(), "GET     / | req-42\n") {
+			t.Errorf("Expected log line ending in the request ID, got %q", buf.String())
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package goexpress
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// NoContent writes a 204 No Content status with an empty body. Any
+// Content-Type or Content-Length header set earlier is removed, since a
+// 204 response carries no body and net/http rejects writes to it.
+func (c *Context) NoContent() {
+	h := c.Writer.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	c.Writer.WriteHeader(http.StatusNoContent)
+}
+
+// bodyAllowed reports whether a response with the given status may
+// include a body.
+func bodyAllowed(status int) bool {
+	switch {
+	case status >= 100 && status <= 199:
+		return false
+	case status == http.StatusNoContent, status == http.StatusNotModified:
+		return false
+	}
+	return true
+}
+
+// render writes body with the given status and content type. For statuses
+// that carry no body, such as 204 and 304, only the status is written. If
+// the handler already wrote the header, the status and content type are
+// left as they were and only the body is appended.
+func (c *Context) render(status int, contentType string, body []byte) error {
+	if !bodyAllowed(status) {
+		c.Writer.WriteHeader(status)
+		return nil
+	}
+
+	if !c.writer.written {
+		h := c.Writer.Header()
+		h.Set("Content-Type", contentType)
+		h.Set("Content-Length", strconv.Itoa(len(body)))
+		c.Writer.WriteHeader(status)
+	}
+	_, err := c.Writer.Write(body)
+	return err
+}
+
+// Data writes data with the given status and content type. If contentType
+// is empty, the default of the matched route is used, see
+// Group.DefaultContentType, or else the type is detected from data with
+// http.DetectContentType.
+func (c *Context) Data(status int, contentType string, data []byte) error {
+	if contentType == "" {
+		contentType = c.writer.contentType
+	}
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return c.render(status, contentType, data)
+}
+
+// JSON encodes v as JSON and writes it with the given status and a
+// Content-Type of application/json. A nil v is written as null.
+//
+// The whole document is encoded into memory before anything is sent, so if
+// encoding fails partway, for example in a MarshalJSON method deep inside
+// v, the error is returned and the response is left untouched for the
+// handler to report as a 500 instead of a truncated 200. The tradeoff is
+// that memory grows with the size of the response; encoding/json buffers
+// the full document even when given a writer, so very large collections
+// should be written in pieces by the handler rather than as one value.
+func (c *Context) JSON(status int, v interface{}) error {
+	if !bodyAllowed(status) {
+		return c.render(status, "", nil)
+	}
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.render(status, "application/json", body)
+}
+
+// String formats according to format and writes the result with the given
+// status and a Content-Type of text/plain.
+func (c *Context) String(status int, format string, args ...interface{}) error {
+	return c.render(status, "text/plain; charset=utf-8", []byte(fmt.Sprintf(format, args...)))
+}
+
+// HTML writes html with the given status and a Content-Type of text/html.
+func (c *Context) HTML(status int, html string) error {
+	return c.render(status, "text/html; charset=utf-8", []byte(html))
+}
+
+// Redirect replies with a redirect to url, which may be absolute or
+// relative to the request path, as with http.Redirect. status must be one
+// of 300, 301, 302, 303, 307 or 308; any other status returns an error
+// and writes nothing. Use 303 See Other after a form POST, and 307 or 308
+// to have the client repeat the request with the same method and body.
+func (c *Context) Redirect(status int, url string) error {
+	switch status {
+	case http.StatusMultipleChoices, http.StatusMovedPermanently, http.StatusFound,
+		http.StatusSeeOther, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return fmt.Errorf("goexpress: invalid redirect status %d", status)
+	}
+	http.Redirect(c.Writer, c.Request, url, status)
+	return nil
+}
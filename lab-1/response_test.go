@@ -0,0 +1,177 @@
+package goexpress
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNoContent verifies that NoContent writes 204 without a body or Content-Type
+func TestNoContent(t *testing.T) {
+	engine := New()
+	engine.DELETE("/users/:id", func(c *Context) {
+		c.Writer.Header().Set("Content-Type", "application/json")
+		c.NoContent()
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/users/1", nil))
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("Expected status 204, got %d", rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "" {
+		t.Errorf("Expected no Content-Type, got %q", ct)
+	}
+	if rr.Body.Len() != 0 {
+		t.Errorf("Expected empty body, got %q", rr.Body.String())
+	}
+}
+
+// TestJSON verifies that JSON writes the encoded value with the right
+// status and Content-Type, and writes nothing when encoding fails
+func TestJSON(t *testing.T) {
+	engine := New()
+	engine.GET("/user", func(c *Context) {
+		c.JSON(http.StatusCreated, map[string]interface{}{"name": "gopher", "age": 13})
+	})
+	engine.GET("/nil", func(c *Context) {
+		c.JSON(http.StatusOK, nil)
+	})
+	engine.GET("/broken", func(c *Context) {
+		if err := c.JSON(http.StatusOK, func() {}); err != nil {
+			c.Writer.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	engine.GET("/notmodified", func(c *Context) {
+		c.JSON(http.StatusNotModified, map[string]string{"ignored": "yes"})
+	})
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"/user", http.StatusCreated, "application/json", `{"age":13,"name":"gopher"}`},
+		{"/nil", http.StatusOK, "application/json", "null"},
+		{"/broken", http.StatusInternalServerError, "", ""},
+		{"/notmodified", http.StatusNotModified, "", ""},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("%s: expected Content-Type %q, got %q", tt.path, tt.contentType, ct)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.body, rr.Body.String())
+		}
+	}
+}
+
+// TestStringAndHTML verifies the text and HTML helpers and that the status
+// is not rewritten once the handler has written the header
+func TestStringAndHTML(t *testing.T) {
+	engine := New()
+	engine.GET("/text", func(c *Context) {
+		c.String(http.StatusOK, "Hello, %s! You are %d.", "gopher", 13)
+	})
+	engine.GET("/html", func(c *Context) {
+		c.HTML(http.StatusAccepted, "<h1>Hello</h1>")
+	})
+	engine.GET("/written", func(c *Context) {
+		c.Writer.WriteHeader(http.StatusCreated)
+		c.String(http.StatusOK, "created")
+	})
+
+	tests := []struct {
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"/text", http.StatusOK, "text/plain; charset=utf-8", "Hello, gopher! You are 13."},
+		{"/html", http.StatusAccepted, "text/html; charset=utf-8", "<h1>Hello</h1>"},
+		{"/written", http.StatusCreated, "", "created"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); tt.contentType != "" && ct != tt.contentType {
+			t.Errorf("%s: expected Content-Type %q, got %q", tt.path, tt.contentType, ct)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.body, rr.Body.String())
+		}
+	}
+}
+
+// failingItem fails to encode, to simulate an error deep inside a response.
+type failingItem struct{}
+
+func (failingItem) MarshalJSON() ([]byte, error) {
+	return nil, errors.New("cannot encode item")
+}
+
+// TestJSONEncodeErrorMidValue verifies that an encoding error deep inside a
+// value leaves the response untouched, so the handler can send a clean 500
+func TestJSONEncodeErrorMidValue(t *testing.T) {
+	engine := New()
+	engine.GET("/items", func(c *Context) {
+		items := []interface{}{map[string]int{"id": 1}, map[string]int{"id": 2}, failingItem{}}
+		if err := c.JSON(http.StatusOK, items); err != nil {
+			c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal error"})
+		}
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/items", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rr.Code)
+	}
+	if rr.Body.String() != `{"error":"internal error"}` {
+		t.Errorf("Expected only the error body, got %q", rr.Body.String())
+	}
+}
+
+// TestRedirect verifies that Redirect sets Location for absolute and
+// relative URLs, and rejects statuses that are not redirects
+func TestRedirect(t *testing.T) {
+	engine := New()
+	engine.GET("/links/:code", func(c *Context) {
+		status, _ := c.QueryInt("status")
+		if err := c.Redirect(status, c.QueryDefault("to", "https://example.com/target")); err != nil {
+			c.String(http.StatusInternalServerError, "%v", err)
+		}
+	})
+
+	tests := []struct {
+		target   string
+		status   int
+		location string
+	}{
+		{"/links/go?status=302", http.StatusFound, "https://example.com/target"},
+		{"/links/go?status=301&to=/home", http.StatusMovedPermanently, "/home"},
+		{"/links/go?status=303&to=login", http.StatusSeeOther, "/links/login"},
+		{"/links/go?status=308&to=../docs?page=2", http.StatusPermanentRedirect, "/docs?page=2"},
+		{"/links/go?status=200", http.StatusInternalServerError, ""},
+		{"/links/go?status=304", http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.target, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("Location"); got != tt.location {
+			t.Errorf("%s: expected Location %q, got %q", tt.target, tt.location, got)
+		}
+	}
+}
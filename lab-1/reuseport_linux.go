@@ -0,0 +1,7 @@
+//go:build linux && !(386 || amd64 || arm)
+
+package goexpress
+
+import "syscall"
+
+const soReusePort = syscall.SO_REUSEPORT
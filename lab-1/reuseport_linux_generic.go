@@ -0,0 +1,9 @@
+//go:build linux && (386 || amd64 || arm)
+
+package goexpress
+
+// soReusePort is SO_REUSEPORT on Linux architectures using the generic
+// socket option numbers, for which the frozen syscall package does not
+// export it. Other architectures, such as mips with 0x200, differ and get
+// it from syscall instead.
+const soReusePort = 0xf
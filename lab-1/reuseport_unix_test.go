@@ -0,0 +1,56 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package goexpress
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestReusePort verifies that two engines with ReusePort enabled
+// can listen on the same port at the same time
+func TestReusePort(t *testing.T) {
+	newEngine := func() *Engine {
+		config := DefaultConfig()
+		config.Port = ":8083"
+		config.ReusePort = true
+		engine := NewWithConfig(config)
+		engine.GET("/", echoHandler)
+		return engine
+	}
+	first, second := newEngine(), newEngine()
+
+	errs := make(chan error, 2)
+	for _, engine := range []*Engine{first, second} {
+		go func(engine *Engine) {
+			errs <- engine.Run()
+		}(engine)
+	}
+
+	// Give both servers a moment to start
+	time.Sleep(200 * time.Millisecond)
+
+	resp, err := http.Get("http://localhost:8083")
+	if err != nil {
+		t.Fatalf("Failed to GET from server: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	for _, engine := range []*Engine{first, second} {
+		if err := engine.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown failed: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Expected both servers to bind the shared port, got %v", err)
+		}
+	}
+}
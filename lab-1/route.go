@@ -0,0 +1,126 @@
+package goexpress
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+)
+
+// Patterns for common path parameter formats, for use with Route.Where.
+const (
+	// UUIDPattern matches a UUID in its canonical hyphenated form.
+	UUIDPattern = `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`
+
+	// IntPattern matches a decimal integer with an optional sign.
+	IntPattern = `[-+]?[0-9]+`
+)
+
+// Route is a registered route. It is returned by the registration methods
+// such as GET so that the route can be refined in the same statement.
+type Route struct {
+	method  string
+	pattern string
+	// params holds the parameter and wildcard names in path order.
+	params     []string
+	handler    HandlerFunc
+	group      *Group
+	predicates []func(*http.Request) bool
+	// constraints holds a compiled pattern per parameter index, or nil.
+	constraints []*regexp.Regexp
+	contentType string
+}
+
+// DefaultContentType sets the Content-Type of responses from the route
+// that write a body without setting one, overriding the default of its
+// group, see Group.DefaultContentType.
+func (rt *Route) DefaultContentType(contentType string) *Route {
+	rt.contentType = contentType
+	return rt
+}
+
+// resolvedContentType returns the default content type of the route, or of
+// its nearest group that sets one.
+func (rt *Route) resolvedContentType() string {
+	if rt.contentType != "" {
+		return rt.contentType
+	}
+	for g := rt.group; g != nil; g = g.parent {
+		if g.contentType != "" {
+			return g.contentType
+		}
+	}
+	return ""
+}
+
+// When restricts the route to requests for which match returns true, in
+// addition to the method and path. It can be called several times; all
+// predicates must pass. Several routes may share a method and path, or
+// differ only in parameter names, as long as every one but the last
+// registered has a predicate or a Where constraint:
+//
+//	app.GET("/items", listV2).When(func(r *http.Request) bool {
+//		return r.Header.Get("API-Version") == "2"
+//	})
+//	app.GET("/items", listV1)
+//
+// For a given path, conditional routes are tried in registration order and
+// the first whose conditions pass handles the request; the unconditional
+// route is the fallback. If no route for the path accepts the request,
+// matching continues with lower-priority routes such as ":param" or
+// "*wildcard" patterns and finally responds 404.
+func (rt *Route) When(match func(*http.Request) bool) *Route {
+	rt.predicates = append(rt.predicates, match)
+	return rt
+}
+
+// conditional reports whether the route has predicates or constraints,
+// which lets other routes share its path.
+func (rt *Route) conditional() bool {
+	return len(rt.predicates) > 0 || rt.constraints != nil
+}
+
+// accepts reports whether every predicate of the route passes for r.
+func (rt *Route) accepts(r *http.Request) bool {
+	for _, match := range rt.predicates {
+		if !match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Where constrains the named path parameter to values that match pattern
+// in full, such as UUIDPattern, IntPattern or a custom regular expression.
+// The pattern is compiled once, here, and applied while matching: a
+// request whose parameter does not match is treated as not matching the
+// route, so routing falls through to other routes that could serve the
+// path, for example "/users/:name" after "/users/:id" constrained to
+// digits, and responds 404 if none does. Where panics if the route has no
+// such parameter or the pattern is invalid.
+func (rt *Route) Where(name, pattern string) *Route {
+	i := slices.Index(rt.params, name)
+	if i < 0 {
+		panic("goexpress: route " + rt.pattern + " has no parameter " + name)
+	}
+	re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+	if err != nil {
+		panic("goexpress: invalid pattern for parameter " + name + ": " + err.Error())
+	}
+
+	if rt.constraints == nil {
+		rt.constraints = make([]*regexp.Regexp, len(rt.params))
+	}
+	rt.constraints[i] = re
+	return rt
+}
+
+// validParams reports whether the captured parameter values, in path
+// order, satisfy the constraints added with Where.
+func (rt *Route) validParams(values []string) bool {
+	for i, re := range rt.constraints {
+		if re != nil && !re.MatchString(values[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package goexpress
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// HandlerFunc defines the request handler used by goexpress routes.
+type HandlerFunc func(c *Context)
+
+// addRoute registers handler for requests matching method and the path
+// pattern. A segment of the form ":name" matches any non-empty path segment
+// and captures it as the parameter name. A final segment of the form
+// "*name" matches the rest of the path, slashes included, and captures it
+// as name. When several routes could match a request, static segments take
+// priority over parameters, which take priority over wildcards. It panics
+// if path does not begin with "/", if handler is nil, if a parameter is
+// unnamed or repeated, if a wildcard is not the last segment, or if an
+// equivalent unconditional route is already registered. The returned
+// Route can be refined further, see Route.When and Route.Where.
+func (e *Engine) addRoute(method, path string, handler HandlerFunc) *Route {
+	rt := newRoute(method, path, handler)
+	root := e.trees[method]
+	if root == nil {
+		root = &node{}
+		e.trees[method] = root
+	}
+	if existing := root.insert(strings.Split(path[1:], "/"), rt); existing != nil {
+		panic("goexpress: route conflicts with " + method + " " + existing.pattern + ": " + path)
+	}
+	if len(rt.params) > e.maxParams {
+		e.maxParams = len(rt.params)
+	}
+	return rt
+}
+
+// newRoute validates path and returns a route for it that is not yet
+// registered, see addRoute.
+func newRoute(method, path string, handler HandlerFunc) *Route {
+	if !strings.HasPrefix(path, "/") {
+		panic("goexpress: path must begin with '/': " + path)
+	}
+	if handler == nil {
+		panic("goexpress: nil handler for " + method + " " + path)
+	}
+
+	rt := &Route{method: method, pattern: path, handler: handler}
+	segments := strings.Split(path[1:], "/")
+	for i, seg := range segments {
+		if !strings.HasPrefix(seg, ":") && !strings.HasPrefix(seg, "*") {
+			continue
+		}
+		name := seg[1:]
+		if name == "" || slices.Contains(rt.params, name) {
+			panic("goexpress: invalid parameter in path: " + path)
+		}
+		if seg[0] == '*' && i != len(segments)-1 {
+			panic("goexpress: wildcard must be the last segment: " + path)
+		}
+		rt.params = append(rt.params, name)
+	}
+	return rt
+}
+
+// GET registers a handler for GET requests to path.
+func (e *Engine) GET(path string, handler HandlerFunc) *Route {
+	return e.addRoute(http.MethodGet, path, handler)
+}
+
+// POST registers a handler for POST requests to path.
+func (e *Engine) POST(path string, handler HandlerFunc) *Route {
+	return e.addRoute(http.MethodPost, path, handler)
+}
+
+// PUT registers a handler for PUT requests to path.
+func (e *Engine) PUT(path string, handler HandlerFunc) *Route {
+	return e.addRoute(http.MethodPut, path, handler)
+}
+
+// PATCH registers a handler for PATCH requests to path.
+func (e *Engine) PATCH(path string, handler HandlerFunc) *Route {
+	return e.addRoute(http.MethodPatch, path, handler)
+}
+
+// DELETE registers a handler for DELETE requests to path.
+func (e *Engine) DELETE(path string, handler HandlerFunc) *Route {
+	return e.addRoute(http.MethodDelete, path, handler)
+}
+
+// GETIf registers a handler for GET requests to path only if condition is
+// true, for endpoints such as debug pages or test fixtures that exist in
+// some environments only:
+//
+//	app.GETIf(env == "dev", "/debug/config", showConfig)
+//
+// If condition is false, requests to path get the usual 404 response. The
+// path is validated either way, and the returned Route can be refined as
+// usual; refinements of an unregistered route have no effect.
+func (e *Engine) GETIf(condition bool, path string, handler HandlerFunc) *Route {
+	if !condition {
+		return newRoute(http.MethodGet, path, handler)
+	}
+	return e.GET(path, handler)
+}
+
+// match returns the route registered for the method and path of r, along
+// with the captured parameters. A HEAD request with no HEAD route of its
+// own is served by the GET route, as net/http drops the body.
+func (e *Engine) match(r *http.Request) (*Route, *params, bool) {
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/") {
+		return nil, nil, false
+	}
+
+	var values []string
+	if e.maxParams > 0 {
+		values = make([]string, 0, e.maxParams)
+	}
+	var rt *Route
+	if root := e.trees[r.Method]; root != nil {
+		rt, values = root.search(r, path[1:], values)
+	}
+	if rt == nil && r.Method == http.MethodHead {
+		if root := e.trees[http.MethodGet]; root != nil {
+			rt, values = root.search(r, path[1:], values[:0])
+		}
+	}
+	if rt == nil {
+		return nil, nil, false
+	}
+	if len(rt.params) == 0 {
+		return rt, nil, true
+	}
+	return rt, &params{names: rt.params, values: values}, true
+}
+
+// allowedMethods returns the sorted methods other than r.Method with a
+// route matching the path of r, including HEAD wherever GET is allowed.
+func (e *Engine) allowedMethods(r *http.Request) []string {
+	path := r.URL.Path
+	if !strings.HasPrefix(path, "/") {
+		return nil
+	}
+
+	var allow []string
+	values := make([]string, 0, e.maxParams)
+	for method, root := range e.trees {
+		if method == r.Method {
+			continue
+		}
+		if rt, _ := root.search(r, path[1:], values[:0]); rt != nil {
+			allow = append(allow, method)
+		}
+	}
+	if slices.Contains(allow, http.MethodGet) && !slices.Contains(allow, http.MethodHead) && r.Method != http.MethodHead {
+		allow = append(allow, http.MethodHead)
+	}
+	slices.Sort(allow)
+	return allow
+}
@@ -0,0 +1,341 @@
+package goexpress
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestRouting verifies that requests are dispatched by method and exact path
+func TestRouting(t *testing.T) {
+	engine := New()
+	engine.GET("/users", func(c *Context) {
+		c.Writer.Write([]byte("List users"))
+	})
+	engine.POST("/users", func(c *Context) {
+		c.Writer.WriteHeader(http.StatusCreated)
+		c.Writer.Write([]byte("User created"))
+	})
+	engine.PUT("/users/1", func(c *Context) {
+		c.Writer.Write([]byte("User updated"))
+	})
+	engine.PATCH("/users/1", func(c *Context) {
+		c.Writer.Write([]byte("User patched"))
+	})
+	engine.DELETE("/users/1", func(c *Context) {
+		c.Writer.Write([]byte("User deleted"))
+	})
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/users", http.StatusOK, "List users"},
+		{http.MethodPost, "/users", http.StatusCreated, "User created"},
+		{http.MethodPut, "/users/1", http.StatusOK, "User updated"},
+		{http.MethodPatch, "/users/1", http.StatusOK, "User patched"},
+		{http.MethodDelete, "/users/1", http.StatusOK, "User deleted"},
+		{http.MethodGet, "/notfound", http.StatusNotFound, "404 page not found\n"},
+		{http.MethodGet, "/users/", http.StatusNotFound, "404 page not found\n"},
+		{http.MethodDelete, "/users", http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, rr.Code)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.path, tt.body, rr.Body.String())
+		}
+	}
+}
+
+// TestDuplicateRoute verifies that registering the same route twice panics
+func TestDuplicateRoute(t *testing.T) {
+	engine := New()
+	engine.GET("/users", echoHandler)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected duplicate route registration to panic")
+		}
+	}()
+	engine.GET("/users", echoHandler)
+}
+
+// TestPathParams verifies parameter extraction and that literal segments
+// take priority over parameter segments
+func TestPathParams(t *testing.T) {
+	engine := New()
+	writeParams := func(names ...string) HandlerFunc {
+		return func(c *Context) {
+			for _, name := range names {
+				fmt.Fprintf(c.Writer, "%s=%s;", name, c.Param(name))
+			}
+		}
+	}
+	engine.GET("/users/:id", writeParams("id", "missing"))
+	engine.GET("/users/new", func(c *Context) {
+		c.Writer.Write([]byte("new user form"))
+	})
+	engine.GET("/users/:uid/posts/:pid", writeParams("uid", "pid"))
+	engine.GET("/users/:uid/posts/latest", writeParams("uid"))
+	engine.GET("/api/:version/users", writeParams("version"))
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/users/42", http.StatusOK, "id=42;missing=;"},
+		{"/users/new", http.StatusOK, "new user form"},
+		{"/users/5/posts/7", http.StatusOK, "uid=5;pid=7;"},
+		{"/users/5/posts/latest", http.StatusOK, "uid=5;"},
+		{"/api/v1/users", http.StatusOK, "version=v1;"},
+		{"/users/", http.StatusNotFound, "404 page not found\n"},
+		{"/users/5/posts", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, rr.Code)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.path, tt.body, rr.Body.String())
+		}
+	}
+
+}
+
+// TestConflictingParamRoute verifies that routes differing only in
+// parameter names are rejected
+func TestConflictingParamRoute(t *testing.T) {
+	engine := New()
+	engine.GET("/users/:id", echoHandler)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected conflicting route registration to panic")
+		}
+	}()
+	engine.GET("/users/:name", echoHandler)
+}
+
+// TestRouteWhen verifies predicate-based routing on the same path and the
+// fallback to the unconditional route
+func TestRouteWhen(t *testing.T) {
+	version := func(v string) func(*http.Request) bool {
+		return func(r *http.Request) bool { return r.Header.Get("API-Version") == v }
+	}
+	respond := func(body string) HandlerFunc {
+		return func(c *Context) { c.String(http.StatusOK, body) }
+	}
+
+	engine := New()
+	engine.GET("/items", respond("v3")).When(version("3"))
+	engine.GET("/items", respond("v2")).When(version("2"))
+	engine.GET("/items", respond("v1"))
+	engine.GET("/orders/:id", respond("json order")).When(func(r *http.Request) bool {
+		return r.Header.Get("Accept") == "application/json"
+	})
+	engine.GET("/orders/*rest", respond("any order"))
+
+	tests := []struct {
+		path   string
+		header string
+		value  string
+		body   string
+	}{
+		{"/items", "API-Version", "3", "v3"},
+		{"/items", "API-Version", "2", "v2"},
+		{"/items", "API-Version", "9", "v1"},
+		{"/items", "", "", "v1"},
+		{"/orders/1", "Accept", "application/json", "json order"},
+		{"/orders/1", "Accept", "text/html", "any order"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s with %s=%q: expected body %q, got %q", tt.path, tt.header, tt.value, tt.body, rr.Body.String())
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a route registered after the fallback to panic")
+		}
+	}()
+	engine.GET("/items", respond("v4")).When(version("4"))
+}
+
+// TestRouteWhere verifies UUID, integer and custom pattern constraints on
+// path parameters
+func TestRouteWhere(t *testing.T) {
+	engine := New()
+	engine.GET("/orders/:id", echoHandler).Where("id", UUIDPattern)
+	engine.GET("/users/:id/page/:n", echoHandler).Where("id", IntPattern).Where("n", `[1-9][0-9]*`)
+	engine.GET("/tags/:slug", echoHandler).Where("slug", `[a-z-]+`)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/orders/123e4567-e89b-12d3-a456-426614174000", http.StatusOK},
+		{"/orders/123", http.StatusNotFound},
+		{"/orders/123e4567-e89b-12d3-a456-426614174000x", http.StatusNotFound},
+		{"/users/42/page/3", http.StatusOK},
+		{"/users/-1/page/3", http.StatusOK},
+		{"/users/abc/page/3", http.StatusNotFound},
+		{"/users/42/page/0", http.StatusNotFound},
+		{"/tags/go-lang", http.StatusOK},
+		{"/tags/Go", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.path, tt.status, rr.Code)
+		}
+	}
+
+	for _, bad := range []func(){
+		func() { New().GET("/a/:id", echoHandler).Where("other", IntPattern) },
+		func() { New().GET("/a/:id", echoHandler).Where("id", `[`) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected invalid Where to panic")
+				}
+			}()
+			bad()
+		}()
+	}
+}
+
+// TestRouteWhereFallThrough verifies that a constrained parameter that does
+// not match lets routing continue with overlapping routes
+func TestRouteWhereFallThrough(t *testing.T) {
+	respond := func(body string) HandlerFunc {
+		return func(c *Context) { c.String(http.StatusOK, body+":"+c.Param("id")+c.Param("name")+c.Param("path")) }
+	}
+
+	engine := New()
+	engine.GET("/users/:id", respond("by id")).Where("id", `\d+`)
+	engine.GET("/users/:name", respond("by name")).Where("name", `[a-z]+`)
+	engine.GET("/files/*path", respond("file")).Where("path", `[a-z/]+\.txt`)
+	engine.GET("/files/:id/raw", respond("raw")).Where("id", `\d+`)
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/users/42", http.StatusOK, "by id:42"},
+		{"/users/alice", http.StatusOK, "by name:alice"},
+		{"/users/Alice", http.StatusNotFound, "404 page not found\n"},
+		{"/files/7/raw", http.StatusOK, "raw:7"},
+		{"/files/abc/raw", http.StatusNotFound, "404 page not found\n"},
+		{"/files/docs/readme.txt", http.StatusOK, "file:docs/readme.txt"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if rr.Code != tt.status || rr.Body.String() != tt.body {
+			t.Errorf("%s: expected %d %q, got %d %q", tt.path, tt.status, tt.body, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+// TestMethodNotAllowed verifies that a path registered for other methods
+// gets a 405 with an Allow header, a custom handler, or a 404 when disabled
+func TestMethodNotAllowed(t *testing.T) {
+	engine := New()
+	engine.GET("/users/:id", echoHandler)
+	engine.PUT("/users/:id", echoHandler)
+	engine.DELETE("/users/:id", echoHandler)
+	engine.POST("/users", echoHandler)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		allow  string
+	}{
+		{http.MethodPost, "/users/5", http.StatusMethodNotAllowed, "DELETE, GET, HEAD, PUT"},
+		{http.MethodGet, "/users", http.StatusMethodNotAllowed, "POST"},
+		{http.MethodHead, "/users", http.StatusMethodNotAllowed, "POST"},
+		{http.MethodHead, "/users/5", http.StatusOK, ""},
+		{http.MethodGet, "/users/5/posts", http.StatusNotFound, ""},
+		{http.MethodGet, "/users/5", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: expected Allow %q, got %q", tt.method, tt.path, tt.allow, got)
+		}
+	}
+
+	engine.SetMethodNotAllowed(func(c *Context) {
+		c.String(http.StatusMethodNotAllowed, "use %s", c.Writer.Header().Get("Allow"))
+	})
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if rr.Code != http.StatusMethodNotAllowed || rr.Body.String() != "use POST" {
+		t.Errorf("Expected custom 405 \"use POST\", got %d %q", rr.Code, rr.Body.String())
+	}
+
+	engine.SetMethodNotAllowed(nil)
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 when disabled, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("Allow"); got != "" {
+		t.Errorf("Expected no Allow header when disabled, got %q", got)
+	}
+}
+
+// TestHeadFallback verifies that HEAD requests are served by the GET route
+// unless a HEAD route of their own is registered
+func TestHeadFallback(t *testing.T) {
+	engine := New()
+	engine.GET("/files/:name", func(c *Context) {
+		c.Writer.Header().Set("X-File", c.Param("name"))
+		c.String(http.StatusOK, "contents")
+	})
+	engine.GET("/ping", echoHandler)
+	engine.addRoute(http.MethodHead, "/ping", func(c *Context) {
+		c.Writer.Header().Set("X-Ping", "head")
+		c.Writer.WriteHeader(http.StatusNoContent)
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodHead, "/files/a.txt", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("X-File"); got != "a.txt" {
+		t.Errorf("Expected X-File %q, got %q", "a.txt", got)
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodHead, "/ping", nil))
+	if rr.Code != http.StatusNoContent || rr.Header().Get("X-Ping") != "head" {
+		t.Errorf("Expected the HEAD route to win, got %d %q", rr.Code, rr.Header().Get("X-Ping"))
+	}
+}
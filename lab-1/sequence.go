@@ -0,0 +1,84 @@
+package goexpress
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// SequenceStore records the last sequence number accepted per session for
+// SequenceGuard. Implementations must be safe for concurrent use.
+type SequenceStore interface {
+	// Advance records seq as the last sequence number of session and
+	// returns true if seq is greater than the one recorded before, or if
+	// none was. Otherwise it leaves the record unchanged and returns
+	// false. The check and the update must happen atomically, so that of
+	// two concurrent requests with the same number only one is accepted.
+	Advance(session string, seq uint64) bool
+}
+
+// MemorySequenceStore is an in-memory SequenceStore. It keeps one entry per
+// session forever, so long-running servers with many short sessions should
+// call Forget when a session ends, or use a store with expiry instead. It
+// is not shared between processes.
+type MemorySequenceStore struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+// NewMemorySequenceStore returns an empty MemorySequenceStore.
+func NewMemorySequenceStore() *MemorySequenceStore {
+	return &MemorySequenceStore{last: make(map[string]uint64)}
+}
+
+// Advance implements SequenceStore.
+func (s *MemorySequenceStore) Advance(session string, seq uint64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.last[session]; ok && seq <= last {
+		return false
+	}
+	s.last[session] = seq
+	return true
+}
+
+// Forget removes the record of session, so its next sequence number may
+// start over.
+func (s *MemorySequenceStore) Forget(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.last, session)
+}
+
+// SequenceGuard returns a middleware that enforces strictly increasing
+// X-Sequence headers within each session, rejecting replayed and
+// out-of-order requests with 409 Conflict. sessionKey identifies the
+// session of a request, for example from a cookie or API key. Requests
+// without a session key, or with a missing or non-numeric X-Sequence, are
+// rejected with 400 Bad Request. A nil store means a new
+// MemorySequenceStore. A number is used up once the request is accepted,
+// even if its handler then fails, so clients must retry with a new number.
+func SequenceGuard(store SequenceStore, sessionKey func(c *Context) string) Middleware {
+	if store == nil {
+		store = NewMemorySequenceStore()
+	}
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			session := sessionKey(c)
+			if session == "" {
+				c.Error(NewHTTPError(http.StatusBadRequest, "missing session"))
+				return
+			}
+			seq, err := strconv.ParseUint(c.Request.Header.Get("X-Sequence"), 10, 64)
+			if err != nil {
+				c.Error(NewHTTPError(http.StatusBadRequest, "missing or invalid X-Sequence header"))
+				return
+			}
+			if !store.Advance(session, seq) {
+				c.Error(NewHTTPError(http.StatusConflict, "out-of-order or replayed X-Sequence"))
+				return
+			}
+			next(c)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestSequenceGuard verifies that sequence numbers must increase within a
+// session and are tracked separately per session
+func TestSequenceGuard(t *testing.T) {
+	engine := New()
+	engine.Use(SequenceGuard(nil, func(c *Context) string {
+		return c.Request.Header.Get("X-Session")
+	}))
+	engine.POST("/orders", echoHandler)
+
+	tests := []struct {
+		session string
+		seq     string
+		status  int
+	}{
+		{"a", "1", http.StatusOK},
+		{"a", "2", http.StatusOK},
+		{"a", "2", http.StatusConflict},
+		{"a", "1", http.StatusConflict},
+		{"b", "1", http.StatusOK},
+		{"a", "5", http.StatusOK},
+		{"a", "4", http.StatusConflict},
+		{"a", "", http.StatusBadRequest},
+		{"a", "-6", http.StatusBadRequest},
+		{"a", "six", http.StatusBadRequest},
+		{"", "7", http.StatusBadRequest},
+		{"a", "6", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("X-Session", tt.session)
+		req.Header.Set("X-Sequence", tt.seq)
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("Session %q sequence %q: expected status %d, got %d", tt.session, tt.seq, tt.status, rr.Code)
+		}
+	}
+}
+
+// TestMemorySequenceStore verifies that concurrent requests with the same
+// sequence number are accepted once, and that Forget resets a session
+func TestMemorySequenceStore(t *testing.T) {
+	store := NewMemorySequenceStore()
+
+	var accepted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if store.Advance("s", 1) {
+				accepted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := accepted.Load(); n != 1 {
+		t.Errorf("Expected one concurrent request to be accepted, got %d", n)
+	}
+
+	store.Forget("s")
+	if !store.Advance("s", 1) {
+		t.Errorf("Expected sequence to start over after Forget")
+	}
+}
@@ -0,0 +1,67 @@
+package goexpress
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// SetBodyReadTimeout limits how long reading the rest of the request body
+// may take, starting now. Once the body has been read to the end the
+// deadline is cleared, so a handler that keeps working afterwards is not
+// affected. Reads that exceed the deadline fail with a timeout error.
+// It replaces r.Body and returns http.ErrNotSupported if w does not
+// support read deadlines, in which case r is left unchanged.
+func SetBodyReadTimeout(w http.ResponseWriter, r *http.Request, d time.Duration) error {
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Now().Add(d)); err != nil {
+		return err
+	}
+	r.Body = &deadlineBody{ReadCloser: r.Body, rc: rc}
+	return nil
+}
+
+// deadlineBody clears the connection read deadline once the body is drained.
+type deadlineBody struct {
+	io.ReadCloser
+	rc      *http.ResponseController
+	cleared bool
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.clear()
+	}
+	return n, err
+}
+
+func (b *deadlineBody) Close() error {
+	b.clear()
+	return b.ReadCloser.Close()
+}
+
+func (b *deadlineBody) clear() {
+	if !b.cleared {
+		b.cleared = true
+		b.rc.SetReadDeadline(time.Time{})
+	}
+}
+
+// SlowBodyGuard returns a middleware that gives clients d to deliver the
+// request body, protecting handlers from slow-POST clients that send the
+// headers quickly and then trickle the body. Unlike Config.ReadTimeout,
+// which covers headers and body for every route, it applies only to the
+// routes it wraps and starts when the handler is reached. Handlers see a
+// timeout error from r.Body once d has elapsed.
+func SlowBodyGuard(d time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			if body := c.Request.Body; body != nil && body != http.NoBody {
+				// Writers without deadline support just run unguarded
+				SetBodyReadTimeout(c.Writer, c.Request, d)
+			}
+			next(c)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package goexpress
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSlowBodyGuard verifies that a body trickled slower than the guard
+// fails to read, while a fast body is read normally and the request
+// context survives after the body is drained
+func TestSlowBodyGuard(t *testing.T) {
+	engine := New()
+	engine.POST("/upload", SlowBodyGuard(100*time.Millisecond)(func(c *Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Writer.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+		// Keep working past the guard after the body has been read
+		time.Sleep(200 * time.Millisecond)
+		if c.Request.Context().Err() != nil {
+			c.Writer.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		c.Writer.Write(body)
+	}))
+	server := httptest.NewServer(engine)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/upload", "text/plain", strings.NewReader("fast"))
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200 for a fast body, got %d", resp.StatusCode)
+	}
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Dial failed: %v", err)
+	}
+	defer conn.Close()
+	io.WriteString(conn, "POST /upload HTTP/1.1\r\nHost: test\r\nContent-Length: 10\r\n\r\nab")
+
+	slow, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	slow.Body.Close()
+	if slow.StatusCode != http.StatusRequestTimeout {
+		t.Errorf("Expected status 408 for a slow body, got %d", slow.StatusCode)
+	}
+}
@@ -0,0 +1,70 @@
+package goexpress
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// StaticFS serves the files of fsys under urlPrefix for GET and HEAD
+// requests, so assets compiled into the binary with //go:embed need no
+// directory next to it at runtime:
+//
+//	//go:embed assets
+//	var assets embed.FS
+//
+//	sub, _ := fs.Sub(assets, "assets")
+//	app.StaticFS("/assets", sub)
+//
+// The request path below urlPrefix is cleaned before it is resolved, so
+// ".." segments cannot escape fsys. A directory is served through its
+// index.html; directories without one are not listed. Missing files get
+// the engine's regular 404 response. Content-Type is detected from the
+// file extension, falling back to sniffing the content. The files are
+// served by http.FileServer over http.FS, which also handles range and
+// conditional requests.
+func (e *Engine) StaticFS(urlPrefix string, fsys fs.FS) {
+	fileServer := http.FileServer(http.FS(fsys))
+	handler := func(c *Context) {
+		rest := c.Param("filepath")
+		name := path.Clean("/" + rest)[1:]
+		if name == "" {
+			name = "."
+		}
+		if !fs.ValidPath(name) || !staticExists(fsys, name) {
+			notFound(c)
+			return
+		}
+
+		urlPath := "/" + name
+		if name == "." {
+			urlPath = "/"
+		} else if strings.HasSuffix(rest, "/") {
+			urlPath += "/"
+		}
+		r := *c.Request
+		u := *r.URL
+		u.Path, u.RawPath = urlPath, ""
+		r.URL = &u
+		fileServer.ServeHTTP(c.Writer, &r)
+	}
+
+	pattern := strings.TrimSuffix(urlPrefix, "/") + "/*filepath"
+	e.GET(pattern, handler)
+	e.addRoute(http.MethodHead, pattern, handler)
+}
+
+// staticExists reports whether name is a file of fsys, or a directory
+// with an index.html.
+func staticExists(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		info, err = fs.Stat(fsys, path.Join(name, "index.html"))
+		return err == nil && !info.IsDir()
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package goexpress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+// TestStaticFS verifies that files are served from an fs.FS with detected
+// content types, index pages and regular 404 responses
+func TestStaticFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":      {Data: []byte("<h1>home</h1>")},
+		"css/site.css":    {Data: []byte("body{}")},
+		"docs/readme.txt": {Data: []byte("read me")},
+	}
+	engine := New()
+	engine.StaticFS("/static/", fsys)
+
+	tests := []struct {
+		method      string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{http.MethodGet, "/static/css/site.css", http.StatusOK, "text/css; charset=utf-8", "body{}"},
+		{http.MethodGet, "/static/docs/readme.txt", http.StatusOK, "text/plain; charset=utf-8", "read me"},
+		{http.MethodGet, "/static/", http.StatusOK, "text/html; charset=utf-8", "<h1>home</h1>"},
+		{http.MethodHead, "/static/css/site.css", http.StatusOK, "text/css; charset=utf-8", ""},
+		{http.MethodGet, "/static/missing.js", http.StatusNotFound, "text/plain; charset=utf-8", "404 page not found\n"},
+		{http.MethodGet, "/static/docs/", http.StatusNotFound, "text/plain; charset=utf-8", "404 page not found\n"},
+		{http.MethodGet, "/static/../static_test.go", http.StatusNotFound, "text/plain; charset=utf-8", "404 page not found\n"},
+		{http.MethodGet, "/static/css/../../../go.mod", http.StatusNotFound, "text/plain; charset=utf-8", "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, httptest.NewRequest(tt.method, tt.path, nil))
+		if rr.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s %s: expected Content-Type %q, got %q", tt.method, tt.path, tt.contentType, got)
+		}
+		if rr.Body.String() != tt.body {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.path, tt.body, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package goexpress
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// StatusPage is the data passed to the templates set with
+// SetStatusTemplate.
+type StatusPage struct {
+	// Status is the HTTP status code, such as 404
+	Status int
+
+	// Title is the standard status text, such as "Not Found"
+	Title string
+
+	// Message is the client message of the error, the status text if the
+	// error did not provide one
+	Message string
+
+	// Path is the request path
+	Path string
+}
+
+// SetHTMLTemplate sets the templates that SetStatusTemplate names refer to.
+func (e *Engine) SetHTMLTemplate(t *template.Template) {
+	e.htmlTemplates = t
+}
+
+// SetStatusTemplate renders errors with the given status code, including
+// the engine's own 404 and 405 responses, with the named template from
+// SetHTMLTemplate when the client accepts text/html, as browsers do. The
+// template receives a StatusPage. Other clients, such as API clients
+// sending "Accept: application/json", still get the response of the
+// ErrorRenderer, for JSON select ProblemJSON. If the template fails to
+// execute, the error is logged and the ErrorRenderer is used as well.
+// It panics if no template by that name was set.
+func (e *Engine) SetStatusTemplate(code int, templateName string) {
+	if e.htmlTemplates == nil || e.htmlTemplates.Lookup(templateName) == nil {
+		panic("goexpress: undefined status template " + strconv.Quote(templateName))
+	}
+	if e.statusTemplates == nil {
+		e.statusTemplates = make(map[int]string)
+	}
+	e.statusTemplates[code] = templateName
+}
+
+// renderStatusPage writes the HTML page for err if one is set for its
+// status and the client accepts HTML, and reports whether it did.
+func (c *Context) renderStatusPage(err error) bool {
+	if c.engine == nil || !acceptsHTML(c.Request.Header.Get("Accept")) {
+		return false
+	}
+	he := httpError(err)
+	name, ok := c.engine.statusTemplates[he.status()]
+	if !ok {
+		return false
+	}
+
+	page := StatusPage{
+		Status:  he.status(),
+		Title:   http.StatusText(he.status()),
+		Message: he.message(),
+		Path:    c.Request.URL.Path,
+	}
+	var buf bytes.Buffer
+	if err := c.engine.htmlTemplates.ExecuteTemplate(&buf, name, page); err != nil {
+		log.Printf("[StatusPage] template %q failed: %v\n", name, err)
+		return false
+	}
+	c.render(page.Status, "text/html; charset=utf-8", buf.Bytes())
+	return true
+}
+
+// acceptsHTML reports whether the Accept header explicitly lists an HTML
+// media type with a nonzero quality. A bare "*/*", as sent by most API
+// clients, does not count.
+func acceptsHTML(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err != nil || v == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
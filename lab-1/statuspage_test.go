@@ -0,0 +1,66 @@
+package goexpress
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestStatusTemplate verifies that error statuses render the configured
+// HTML template for browsers and the error renderer for API clients
+func TestStatusTemplate(t *testing.T) {
+	engine := New()
+	engine.Use(Recover())
+	engine.SetErrorRenderer(ProblemJSON)
+	engine.SetHTMLTemplate(template.Must(template.New("").Parse(
+		`{{define "404.html"}}<h1>{{.Title}}</h1><p>{{.Message}}: {{.Path}}</p>{{end}}` +
+			`{{define "500.html"}}<h1>{{.Status}} oops</h1>{{end}}`)))
+	engine.SetStatusTemplate(http.StatusNotFound, "404.html")
+	engine.SetStatusTemplate(http.StatusInternalServerError, "500.html")
+	engine.GET("/panic", func(c *Context) {
+		panic("boom")
+	})
+	engine.GET("/gone", func(c *Context) {
+		c.Error(NewHTTPError(http.StatusGone, ""))
+	})
+
+	const browser = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	tests := []struct {
+		path        string
+		accept      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"/missing<b>", browser, http.StatusNotFound, "text/html; charset=utf-8",
+			"<h1>Not Found</h1><p>404 page not found: /missing&lt;b&gt;</p>"},
+		{"/panic", browser, http.StatusInternalServerError, "text/html; charset=utf-8", "<h1>500 oops</h1>"},
+		{"/missing", "application/json", http.StatusNotFound, "application/problem+json", ""},
+		{"/missing", "*/*", http.StatusNotFound, "application/problem+json", ""},
+		{"/missing", "text/html;q=0", http.StatusNotFound, "application/problem+json", ""},
+		{"/gone", browser, http.StatusGone, "application/problem+json", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept", tt.accept)
+		rr := httptest.NewRecorder()
+		engine.ServeHTTP(rr, req)
+		if rr.Code != tt.status {
+			t.Errorf("%s %q: expected status %d, got %d", tt.path, tt.accept, tt.status, rr.Code)
+		}
+		if got := rr.Header().Get("Content-Type"); got != tt.contentType {
+			t.Errorf("%s %q: expected Content-Type %q, got %q", tt.path, tt.accept, tt.contentType, got)
+		}
+		if tt.body != "" && rr.Body.String() != tt.body {
+			t.Errorf("%s %q: expected body %q, got %q", tt.path, tt.accept, tt.body, rr.Body.String())
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for undefined template")
+		}
+	}()
+	engine.SetStatusTemplate(http.StatusForbidden, "403.html")
+}
@@ -0,0 +1,179 @@
+package goexpress
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultStreamBufferSize is the buffer size of a BufferedFlushWriter
+	// created with a zero size.
+	DefaultStreamBufferSize = 32 << 10
+
+	// DefaultStreamFlushInterval is the flush interval of a
+	// BufferedFlushWriter created with a zero interval.
+	DefaultStreamFlushInterval = 100 * time.Millisecond
+)
+
+// BufferedFlushWriter buffers writes to a streamed response and sends them
+// to the client whenever the buffer is full, and at the latest one flush
+// interval after data was first buffered, so a slow trickle of rows still
+// reaches the client promptly even if the producer then stalls. Sending
+// blocks while the client is not reading, which holds back the producer
+// loop instead of buffering the whole result in memory. Once the client
+// has disconnected or a write has failed, every later Write and Flush
+// returns that error immediately. Timed flushes run on their own
+// goroutine, so the writer must be closed with Close before the handler
+// returns; StreamFunc does that.
+type BufferedFlushWriter struct {
+	mu       sync.Mutex
+	buf      *bufio.Writer
+	rc       *http.ResponseController
+	ctx      context.Context
+	interval time.Duration
+	timer    *time.Timer
+	armed    bool
+	closed   bool
+	err      error
+}
+
+// NewBufferedFlushWriter returns a BufferedFlushWriter for w with a buffer
+// of size bytes that flushes buffered data after interval. Zero values
+// select DefaultStreamBufferSize and DefaultStreamFlushInterval. Writes
+// fail once ctx, normally the request context, is done.
+func NewBufferedFlushWriter(ctx context.Context, w http.ResponseWriter, size int, interval time.Duration) *BufferedFlushWriter {
+	if size <= 0 {
+		size = DefaultStreamBufferSize
+	}
+	if interval <= 0 {
+		interval = DefaultStreamFlushInterval
+	}
+	return &BufferedFlushWriter{
+		buf:      bufio.NewWriterSize(w, size),
+		rc:       http.NewResponseController(w),
+		ctx:      ctx,
+		interval: interval,
+	}
+}
+
+// Write buffers p, sending it on once the buffer is full, and schedules a
+// flush of whatever is still buffered after the flush interval.
+func (w *BufferedFlushWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.check(); err != nil {
+		return 0, err
+	}
+	n, err := w.buf.Write(p)
+	if err != nil {
+		w.err = err
+		return n, err
+	}
+	if w.buf.Buffered() > 0 && !w.armed {
+		w.armed = true
+		if w.timer == nil {
+			w.timer = time.AfterFunc(w.interval, w.timedFlush)
+		} else {
+			w.timer.Reset(w.interval)
+		}
+	}
+	return n, nil
+}
+
+// Flush sends the buffered data to the client now.
+func (w *BufferedFlushWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.flush()
+}
+
+// Close stops timed flushes and sends what is still buffered. The writer
+// must not be used afterwards.
+func (w *BufferedFlushWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+	return w.flush()
+}
+
+// timedFlush runs on the timer goroutine one interval after data was
+// buffered.
+func (w *BufferedFlushWriter) timedFlush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.armed = false
+	if w.closed || w.buf.Buffered() == 0 {
+		return
+	}
+	w.flush()
+}
+
+// flush sends the buffered data to the client. w.mu must be held.
+func (w *BufferedFlushWriter) flush() error {
+	if err := w.check(); err != nil {
+		return err
+	}
+	if err := w.buf.Flush(); err != nil {
+		w.err = err
+		return err
+	}
+	if err := w.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		w.err = err
+		return err
+	}
+	return nil
+}
+
+// check returns the sticky error, recording a disconnected client first.
+// w.mu must be held.
+func (w *BufferedFlushWriter) check() error {
+	if w.err == nil {
+		w.err = w.ctx.Err()
+	}
+	return w.err
+}
+
+// StreamFunc writes a response of unknown length produced by fn, such as
+// rows pulled from a database cursor:
+//
+//	err := c.StreamFunc(http.StatusOK, "application/x-ndjson", func(w *BufferedFlushWriter) error {
+//		for rows.Next() {
+//			...
+//			if err := enc.Encode(row); err != nil {
+//				return err // client gone or write failed: stop and release the cursor
+//			}
+//		}
+//		return rows.Err()
+//	})
+//
+// The status and content type are sent before fn runs. The writer uses
+// DefaultStreamBufferSize and DefaultStreamFlushInterval; handlers that
+// need other values can create one with NewBufferedFlushWriter instead.
+// fn should return as soon as a write fails, which happens as soon as the
+// client disconnects. StreamFunc closes the writer once fn returns,
+// sending what is left, and returns fn's error, or else the final flush
+// error. As the status is already sent, a failure midway leaves a
+// truncated response, which the caller can only log.
+func (c *Context) StreamFunc(status int, contentType string, fn func(w *BufferedFlushWriter) error) error {
+	h := c.Writer.Header()
+	h.Set("Content-Type", contentType)
+	h.Del("Content-Length")
+	c.Writer.WriteHeader(status)
+
+	w := NewBufferedFlushWriter(c.Request.Context(), c.Writer, 0, 0)
+	err := fn(w)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
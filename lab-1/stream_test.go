@@ -0,0 +1,176 @@
+package goexpress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestStreamFunc verifies that streamed rows reach the client with the
+// given status and content type, and that fn errors are returned
+func TestStreamFunc(t *testing.T) {
+	var streamErr error
+	engine := New()
+	engine.GET("/export", func(c *Context) {
+		streamErr = c.StreamFunc(http.StatusOK, "text/csv", func(w *BufferedFlushWriter) error {
+			for i := 0; i < 3; i++ {
+				if _, err := fmt.Fprintf(w, "row%d\n", i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	engine.GET("/broken", func(c *Context) {
+		streamErr = c.StreamFunc(http.StatusOK, "text/csv", func(w *BufferedFlushWriter) error {
+			fmt.Fprintln(w, "row0")
+			return errors.New("cursor failed")
+		})
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/export", nil))
+	if streamErr != nil {
+		t.Errorf("Expected no error, got %v", streamErr)
+	}
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("Expected 200 text/csv, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if rr.Body.String() != "row0\nrow1\nrow2\n" || !rr.Flushed {
+		t.Errorf("Expected flushed rows, got %q (flushed: %v)", rr.Body.String(), rr.Flushed)
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/broken", nil))
+	if streamErr == nil || streamErr.Error() != "cursor failed" {
+		t.Errorf("Expected fn error to be returned, got %v", streamErr)
+	}
+}
+
+// syncRecorder is a ResponseRecorder that can be read while a timed flush
+// writes to it.
+type syncRecorder struct {
+	mu sync.Mutex
+	rr *httptest.ResponseRecorder
+}
+
+func (r *syncRecorder) Header() http.Header {
+	return r.rr.Header()
+}
+
+func (r *syncRecorder) WriteHeader(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rr.WriteHeader(status)
+}
+
+func (r *syncRecorder) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rr.Write(b)
+}
+
+func (r *syncRecorder) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rr.Flush()
+}
+
+// body returns what was written so far and whether it was flushed.
+func (r *syncRecorder) body() (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rr.Body.String(), r.rr.Flushed
+}
+
+// waitFlushed waits up to a second for want to be written and flushed.
+func (r *syncRecorder) waitFlushed(t *testing.T, want string) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		body, flushed := r.body()
+		if body == want && flushed {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %q to be flushed by the timer, got %q (flushed: %v)", want, body, flushed)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestBufferedFlushWriterInterval verifies that a single small write is
+// flushed after the interval even though no further write follows
+func TestBufferedFlushWriterInterval(t *testing.T) {
+	rec := &syncRecorder{rr: httptest.NewRecorder()}
+	w := NewBufferedFlushWriter(context.Background(), rec, 0, 20*time.Millisecond)
+	defer w.Close()
+
+	w.Write([]byte("first\n"))
+	if body, _ := rec.body(); body != "" {
+		t.Errorf("Expected the row to stay buffered at first, got %q", body)
+	}
+
+	rec.waitFlushed(t, "first\n")
+
+	// A later write schedules another flush
+	w.Write([]byte("second\n"))
+	rec.waitFlushed(t, "first\nsecond\n")
+}
+
+// TestBufferedFlushWriterSize verifies that data is sent once the buffer
+// is full, and that Close sends the rest and stops timed flushes
+func TestBufferedFlushWriterSize(t *testing.T) {
+	rec := &syncRecorder{rr: httptest.NewRecorder()}
+	w := NewBufferedFlushWriter(context.Background(), rec, 8, time.Hour)
+
+	w.Write([]byte("0123456789"))
+	if body, _ := rec.body(); body != "0123456789" {
+		t.Errorf("Expected a write beyond the buffer size to be sent, got %q", body)
+	}
+	w.Write([]byte("abc"))
+	if body, _ := rec.body(); body != "0123456789" {
+		t.Errorf("Expected a small write to stay buffered, got %q", body)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected Close to succeed, got %v", err)
+	}
+	if body, flushed := rec.body(); body != "0123456789abc" || !flushed {
+		t.Errorf("Expected Close to flush the rest, got %q (flushed: %v)", body, flushed)
+	}
+}
+
+// TestStreamFuncClientGone verifies that writes fail as soon as the client
+// disconnects, so the producer loop can stop
+func TestStreamFuncClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	rows := 0
+	var streamErr error
+	engine := New()
+	engine.GET("/export", func(c *Context) {
+		streamErr = c.StreamFunc(http.StatusOK, "text/plain", func(w *BufferedFlushWriter) error {
+			for {
+				if _, err := w.Write([]byte(strings.Repeat("x", 100))); err != nil {
+					return err
+				}
+				if rows++; rows == 10 {
+					cancel()
+				}
+			}
+		})
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(ctx))
+	if !errors.Is(streamErr, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", streamErr)
+	}
+	if rows != 10 {
+		t.Errorf("Expected the loop to stop right after the disconnect, wrote %d rows", rows)
+	}
+}
@@ -0,0 +1,157 @@
+package goexpress
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Timeout returns a middleware that gives the rest of the chain d to
+// respond. The request context gets a deadline of d, so database calls,
+// outgoing requests and anything else that honours r.Context() are
+// cancelled when it passes. If the handler has not returned by then, the
+// client gets 503 Service Unavailable through Context.Error.
+//
+// To make that safe, the handler runs on its own goroutine with a Context
+// whose Writer buffers the response; it is copied to the client only if
+// the handler returns in time. Deciding between the two outcomes is
+// serialized: if the deadline wins, every later write by the handler
+// fails with http.ErrHandlerTimeout and its response is discarded, and if
+// the handler's return is observed first, its response is sent even when
+// the deadline passes a moment later. A handler that ignores its context
+// keeps running in the background after the 503 is sent, so handlers must
+// still return promptly once the context is done. Handlers that call
+// Context.HandleDeadline respond to the deadline themselves, typically with
+// partial results, and are waited for instead of getting the 503. Because
+// of the buffering, flushing is not supported and Timeout does not suit
+// streaming responses. Unlike Config.ReadTimeout and Config.WriteTimeout,
+// which bound socket I/O, it bounds the time spent in the handler. A panic
+// in the handler before the deadline is re-raised on the request
+// goroutine, so Recover still sees it; a panic after the 503 has been sent
+// cannot be, and is logged with its stack trace instead.
+func Timeout(d time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c *Context) {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{header: make(http.Header)}
+			tc := c.derive(tw, c.Request.WithContext(ctx))
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						if p != http.ErrAbortHandler {
+							p = fmt.Sprintf("%v\n\n%s", p, debug.Stack())
+						}
+						// After the deadline nobody receives the panic, so it
+						// is logged here instead of being lost
+						tw.mu.Lock()
+						defer tw.mu.Unlock()
+						if !tw.timedOut {
+							panicked <- p
+						} else if p != http.ErrAbortHandler {
+							log.Printf("[Timeout] panic after the request timed out: %v\n", p)
+						}
+					}
+				}()
+				next(tc)
+				close(done)
+			}()
+
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+				tw.copyTo(c.Writer)
+			case <-ctx.Done():
+				tw.mu.Lock()
+				select {
+				case <-done:
+					tw.mu.Unlock()
+					tw.copyTo(c.Writer)
+					return
+				case p := <-panicked:
+					tw.mu.Unlock()
+					panic(p)
+				default:
+				}
+				if tw.handlesDeadline && ctx.Err() == context.DeadlineExceeded {
+					tw.mu.Unlock()
+					select {
+					case p := <-panicked:
+						panic(p)
+					case <-done:
+					}
+					tw.copyTo(c.Writer)
+					return
+				}
+				tw.timedOut = true
+				tw.mu.Unlock()
+				if ctx.Err() == context.DeadlineExceeded {
+					c.Error(NewHTTPError(http.StatusServiceUnavailable, "request timed out"))
+				}
+			}
+		}
+	}
+}
+
+// timeoutWriter buffers the response of a handler run by Timeout.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	written  bool
+	timedOut bool
+
+	// handlesDeadline is set by Context.HandleDeadline.
+	handlesDeadline bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut || w.written {
+		return
+	}
+	w.status = status
+	w.written = true
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !w.written {
+		w.status = http.StatusOK
+		w.written = true
+	}
+	return w.body.Write(b)
+}
+
+// copyTo sends the buffered header and body to dst.
+func (w *timeoutWriter) copyTo(dst http.ResponseWriter) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	h := dst.Header()
+	for name, values := range w.header {
+		h[name] = values
+	}
+	if w.written {
+		dst.WriteHeader(w.status)
+		dst.Write(w.body.Bytes())
+	}
+}
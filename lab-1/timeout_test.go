@@ -0,0 +1,167 @@
+package goexpress
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTimeout verifies that slow handlers get 503 with a cancelled context
+// and that fast handlers' responses are passed through intact
+func TestTimeout(t *testing.T) {
+	lateWrite := make(chan error, 1)
+	// released is closed once the 503 has been sent, so the late write
+	// below is certain to happen after the deadline has won
+	released := make(chan struct{})
+	engine := New()
+	engine.Use(Timeout(50 * time.Millisecond))
+	engine.GET("/fast", func(c *Context) {
+		c.Writer.Header().Set("X-Handler", "fast")
+		c.String(http.StatusCreated, "done")
+	})
+	engine.GET("/slow", func(c *Context) {
+		select {
+		case <-c.Request.Context().Done():
+		case <-time.After(time.Second):
+			t.Errorf("Expected the request context to be cancelled")
+		}
+		<-released
+		_, err := c.Writer.Write([]byte("too late"))
+		lateWrite <- err
+	})
+	engine.GET("/silent", func(c *Context) {})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if rr.Code != http.StatusCreated || rr.Body.String() != "done" || rr.Header().Get("X-Handler") != "fast" {
+		t.Errorf("Expected fast response to pass through, got %d %q %v", rr.Code, rr.Body.String(), rr.Header())
+	}
+
+	rr = httptest.NewRecorder()
+	begin := time.Now()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if elapsed := time.Since(begin); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected timeout after about 50ms, took %v", elapsed)
+	}
+	if rr.Code != http.StatusServiceUnavailable || rr.Body.String() != "request timed out\n" {
+		t.Errorf("Expected 503 timeout response, got %d %q", rr.Code, rr.Body.String())
+	}
+	close(released)
+	if err := <-lateWrite; err != http.ErrHandlerTimeout {
+		t.Errorf("Expected late write to fail with ErrHandlerTimeout, got %v", err)
+	}
+	if strings.Contains(rr.Body.String(), "too late") {
+		t.Errorf("Expected late write to be discarded, got %q", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/silent", nil))
+	if rr.Code != http.StatusOK || rr.Body.Len() != 0 {
+		t.Errorf("Expected implicit empty 200, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+// TestTimeoutPanic verifies that a handler panic is re-raised on the
+// request goroutine where Recover handles it
+func TestTimeoutPanic(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	engine := New()
+	engine.Use(Recover(), Timeout(time.Second))
+	engine.GET("/panic", func(c *Context) {
+		panic("boom")
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if rr.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rr.Code)
+	}
+}
+
+// TestTimeoutHandleDeadline verifies that a handler opting into the
+// deadline sends its partial results instead of the 503
+func TestTimeoutHandleDeadline(t *testing.T) {
+	engine := New()
+	engine.Use(Timeout(50 * time.Millisecond))
+	engine.GET("/aggregate", func(c *Context) {
+		c.HandleDeadline()
+		results := map[string]string{"fast": "ok"}
+		<-c.Request.Context().Done()
+		if !c.DeadlineReached() {
+			t.Errorf("Expected DeadlineReached after the deadline")
+		}
+		c.PartialJSON(http.StatusOK, results)
+	})
+	engine.GET("/complete", func(c *Context) {
+		if c.DeadlineReached() {
+			t.Errorf("Expected DeadlineReached to be false before the deadline")
+		}
+		c.HandleDeadline()
+		c.JSON(http.StatusOK, map[string]string{"fast": "ok"})
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/aggregate", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rr.Code)
+	}
+	if got := rr.Header().Get("X-Partial-Response"); got != "true" {
+		t.Errorf("Expected X-Partial-Response true, got %q", got)
+	}
+	if rr.Body.String() != `{"fast":"ok"}` {
+		t.Errorf("Expected partial results, got %q", rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/complete", nil))
+	if rr.Code != http.StatusOK || rr.Header().Get("X-Partial-Response") != "" {
+		t.Errorf("Expected a complete 200 response, got %d with X-Partial-Response %q",
+			rr.Code, rr.Header().Get("X-Partial-Response"))
+	}
+}
+
+// TestTimeoutLatePanic verifies that a panic after the deadline, which no
+// longer reaches Recover, is logged with its stack trace
+func TestTimeoutLatePanic(t *testing.T) {
+	pr, pw := io.Pipe()
+	log.SetOutput(pw)
+	defer log.SetOutput(os.Stderr)
+	logged := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 64<<10)
+		n, _ := pr.Read(buf)
+		logged <- string(buf[:n])
+	}()
+
+	released := make(chan struct{})
+	engine := New()
+	engine.Use(Recover(), Timeout(20*time.Millisecond))
+	engine.GET("/late", func(c *Context) {
+		<-c.Request.Context().Done()
+		<-released
+		panic("late boom")
+	})
+
+	rr := httptest.NewRecorder()
+	engine.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/late", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rr.Code)
+	}
+	close(released)
+
+	select {
+	case line := <-logged:
+		if !strings.Contains(line, "[Timeout] panic after the request timed out: late boom") || !strings.Contains(line, "goroutine") {
+			t.Errorf("Expected the late panic to be logged with its stack, got %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("Expected the late panic to be logged")
+	}
+}
@@ -0,0 +1,104 @@
+package goexpress
+
+import (
+	"net/http"
+	"strings"
+)
+
+// node is a path segment in the routing tree of one HTTP method. Children
+// are tried in priority order: static segments, then the ":param" child,
+// then the "*wildcard" child. Parameter names are kept on the route rather
+// than on the node, so routes may name the parameter at the same position
+// differently. A node may hold several routes when all but the last one
+// are conditional, see Route.When and Route.Where.
+type node struct {
+	static   map[string]*node
+	param    *node
+	wildcard *node
+	routes   []*Route
+}
+
+// insert adds rt to the tree under its pattern segments. It returns the
+// unconditional route already registered for the same shape, if any,
+// without adding rt.
+func (n *node) insert(segments []string, rt *Route) *Route {
+	for _, seg := range segments {
+		var child **node
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			child = &n.param
+		case strings.HasPrefix(seg, "*"):
+			child = &n.wildcard
+		default:
+			if n.static == nil {
+				n.static = make(map[string]*node)
+			}
+			if n.static[seg] == nil {
+				n.static[seg] = &node{}
+			}
+			n = n.static[seg]
+			continue
+		}
+		if *child == nil {
+			*child = &node{}
+		}
+		n = *child
+	}
+
+	for _, existing := range n.routes {
+		if !existing.conditional() {
+			return existing
+		}
+	}
+	n.routes = append(n.routes, rt)
+	return nil
+}
+
+// route returns the first route of n whose parameter constraints accept
+// values and whose predicates accept r. Predicated routes are tried in
+// registration order, and the unconditional route, if any, is registered
+// last and so acts as the fallback.
+func (n *node) route(r *http.Request, values []string) *Route {
+	for _, rt := range n.routes {
+		if rt.validParams(values) && rt.accepts(r) {
+			return rt
+		}
+	}
+	return nil
+}
+
+// search matches path, the remainder of the request path after the slash
+// that precedes this node's children. Captured parameter values are
+// appended to values in path order. It backtracks when a higher-priority
+// branch fails deeper in the tree, so "/users/new/edit" can still match
+// "/users/:id/edit" when "/users/new" is also registered.
+func (n *node) search(r *http.Request, path string, values []string) (*Route, []string) {
+	seg, rest, more := strings.Cut(path, "/")
+
+	if child := n.static[seg]; child != nil {
+		if rt, v := child.next(r, rest, more, values); rt != nil {
+			return rt, v
+		}
+	}
+	if n.param != nil && seg != "" {
+		if rt, v := n.param.next(r, rest, more, append(values, seg)); rt != nil {
+			return rt, v
+		}
+	}
+	if n.wildcard != nil {
+		values = append(values, path)
+		if rt := n.wildcard.route(r, values); rt != nil {
+			return rt, values
+		}
+	}
+	return nil, nil
+}
+
+// next continues the search below n, or returns the route of n when the
+// request path has no segments left.
+func (n *node) next(r *http.Request, rest string, more bool, values []string) (*Route, []string) {
+	if !more {
+		return n.route(r, values), values
+	}
+	return n.search(r, rest, values)
+}
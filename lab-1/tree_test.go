@@ -0,0 +1,188 @@
+package goexpress
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestTreeMatch verifies static, parameter and wildcard matching and their priority
+func TestTreeMatch(t *testing.T) {
+	engine := New()
+	patterns := []string{
+		"/",
+		"/users",
+		"/users/new",
+		"/users/:id",
+		"/users/:id/edit",
+		"/users/:id/posts/:pid",
+		"/files/*filepath",
+		"/files/readme",
+		"/files/:name/raw",
+	}
+	for _, p := range patterns {
+		engine.GET(p, echoHandler)
+	}
+
+	tests := []struct {
+		path    string
+		pattern string
+		params  map[string]string
+	}{
+		{"/", "/", nil},
+		{"/users", "/users", nil},
+		{"/users/new", "/users/new", nil},
+		{"/users/123", "/users/:id", map[string]string{"id": "123"}},
+		{"/users/new/edit", "/users/:id/edit", map[string]string{"id": "new"}},
+		{"/users/5/posts/9", "/users/:id/posts/:pid", map[string]string{"id": "5", "pid": "9"}},
+		{"/files/readme", "/files/readme", nil},
+		{"/files/a/raw", "/files/:name/raw", map[string]string{"name": "a"}},
+		{"/files/css/style.css", "/files/*filepath", map[string]string{"filepath": "css/style.css"}},
+		{"/files/readme/raw/x", "/files/*filepath", map[string]string{"filepath": "readme/raw/x"}},
+		{"/files/", "/files/*filepath", map[string]string{"filepath": ""}},
+		{"/files", "", nil},
+		{"/users/", "", nil},
+		{"/users/5/posts", "", nil},
+		{"/unknown", "", nil},
+	}
+	for _, tt := range tests {
+		rt, ps, ok := engine.match(httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if tt.pattern == "" {
+			if ok {
+				t.Errorf("%s: expected no match, got %s", tt.path, rt.pattern)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("%s: expected match %s, got none", tt.path, tt.pattern)
+			continue
+		}
+		if rt.pattern != tt.pattern {
+			t.Errorf("%s: expected match %s, got %s", tt.path, tt.pattern, rt.pattern)
+		}
+		for name, want := range tt.params {
+			if got := ps.get(name); got != want {
+				t.Errorf("%s: expected %s=%q, got %q", tt.path, name, want, got)
+			}
+		}
+	}
+}
+
+// TestInvalidPatterns verifies that malformed patterns are rejected at registration
+func TestInvalidPatterns(t *testing.T) {
+	patterns := []string{"users", "/users/:", "/files/*", "/files/*path/more", "/a/:id/:id"}
+	for _, p := range patterns {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected pattern %q to panic", p)
+				}
+			}()
+			New().GET(p, echoHandler)
+		}()
+	}
+}
+
+// naiveRoute and naiveRouter reproduce the original linear-scan matcher
+// as a baseline for the tree benchmarks.
+type naiveRoute struct {
+	segments []string
+	handler  HandlerFunc
+}
+
+type naiveRouter struct {
+	routes []*naiveRoute
+}
+
+func (nr *naiveRouter) add(pattern string, handler HandlerFunc) {
+	nr.routes = append(nr.routes, &naiveRoute{
+		segments: strings.Split(pattern[1:], "/"),
+		handler:  handler,
+	})
+}
+
+func (nr *naiveRouter) match(path string) (HandlerFunc, map[string]string) {
+	segments := strings.Split(path[1:], "/")
+	for _, rt := range nr.routes {
+		params := make(map[string]string)
+		ok := true
+		for i, seg := range rt.segments {
+			if strings.HasPrefix(seg, "*") {
+				params[seg[1:]] = strings.Join(segments[i:], "/")
+				return rt.handler, params
+			}
+			if i >= len(segments) {
+				ok = false
+				break
+			}
+			if strings.HasPrefix(seg, ":") {
+				params[seg[1:]] = segments[i]
+			} else if seg != segments[i] {
+				ok = false
+				break
+			}
+		}
+		if ok && len(segments) == len(rt.segments) {
+			return rt.handler, params
+		}
+	}
+	return nil, nil
+}
+
+// benchmarkPatterns returns a route table of a few hundred routes.
+func benchmarkPatterns() []string {
+	var patterns []string
+	for i := 0; i < 100; i++ {
+		patterns = append(patterns,
+			fmt.Sprintf("/api/v1/resource%d", i),
+			fmt.Sprintf("/api/v1/resource%d/:id", i),
+			fmt.Sprintf("/api/v1/resource%d/:id/items/:item", i),
+		)
+	}
+	return append(patterns, "/static/*filepath")
+}
+
+var benchmarkPaths = []string{
+	"/api/v1/resource99",
+	"/api/v1/resource99/42",
+	"/api/v1/resource99/42/items/7",
+	"/static/css/app/style.css",
+}
+
+func BenchmarkTreeMatch(b *testing.B) {
+	engine := New()
+	for _, p := range benchmarkPatterns() {
+		engine.GET(p, echoHandler)
+	}
+	var requests []*http.Request
+	for _, path := range benchmarkPaths {
+		requests = append(requests, httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, req := range requests {
+			if _, _, ok := engine.match(req); !ok {
+				b.Fatalf("no match for %s", req.URL.Path)
+			}
+		}
+	}
+}
+
+func BenchmarkNaiveMatch(b *testing.B) {
+	nr := &naiveRouter{}
+	for _, p := range benchmarkPatterns() {
+		nr.add(p, echoHandler)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range benchmarkPaths {
+			if h, _ := nr.match(path); h == nil {
+				b.Fatalf("no match for %s", path)
+			}
+		}
+	}
+}
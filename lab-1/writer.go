@@ -0,0 +1,61 @@
+package goexpress
+
+import "net/http"
+
+// responseWriter wraps an http.ResponseWriter to record the response
+// status and make sure the header is written only once.
+type responseWriter struct {
+	http.ResponseWriter
+	status  int
+	written bool
+
+	// contentType is set on responses with a body that have no
+	// Content-Type when the header is written.
+	contentType string
+}
+
+// reset points the wrapper at w for a new response.
+func (w *responseWriter) reset(rw http.ResponseWriter) {
+	w.ResponseWriter = rw
+	w.status = http.StatusOK
+	w.written = false
+	w.contentType = ""
+}
+
+// WriteHeader sends the status code unless the header was already written,
+// in which case the call is ignored.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.written {
+		return
+	}
+	w.status = status
+	w.written = true
+	if w.contentType != "" && bodyAllowed(status) {
+		if h := w.Header(); h.Get("Content-Type") == "" {
+			h.Set("Content-Type", w.contentType)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write writes the body, sending a 200 status first if none was written.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends any buffered data to the client if the underlying writer
+// supports it.
+func (w *responseWriter) Flush() {
+	if !w.written {
+		w.WriteHeader(http.StatusOK)
+	}
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}